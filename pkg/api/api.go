@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/cases"
@@ -35,93 +37,120 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 	slog.Debug("parsing openapi", "pathPrefix", pathPrefix)
 	resourceBySingular := make(map[string]*Resource)
 	customMethodsByPattern := make(map[string][]*CustomMethod)
+	collectionCustomMethodsByPattern := make(map[string][]*CustomMethod)
+	batchMethodsByPattern := make(map[string][]*Methods)
+	undeleteMethodsByPattern := make(map[string]*UndeleteMethod)
+	revisionMethodsByPattern := make(map[string][]*Methods)
 	// we try to parse the paths to find possible resources, since
 	// they may not always be annotated as such.
-	for path, pathItem := range api.Paths {
+	// iterate in a stable order, so that the primary pattern of a
+	// resource reachable from multiple paths is deterministic.
+	paths := slices.Sorted(maps.Keys(api.Paths))
+	for _, path := range paths {
+		pathItem := api.Paths[path]
 		path = path[len(pathPrefix):]
 		slog.Debug("path", "path", path)
+		// revision paths extend the path of a resource, and must not be
+		// mistaken for the paths of another resource.
+		if pattern, revisionMethods := parseRevisionMethod(api, path, pathItem); revisionMethods != nil {
+			revisionMethodsByPattern[pattern] = append(revisionMethodsByPattern[pattern], revisionMethods)
+			continue
+		}
+		if strings.Contains(path, "@") {
+			slog.Debug("path is not a revision of a resource", "path", path)
+			continue
+		}
 		var r Resource
 		var sRef *openapi.Schema
 		p := getPatternInfo(path)
 		var lroDetails *openapi.XAEPLongRunningOperation
+		singleton := false
 		if p == nil { // not a resource pattern
 			slog.Debug("path is not a resource", "path", path)
 			continue
 		}
 		slog.Debug("parsing path for resource", "path", path)
-		if p.CustomMethodName != "" && p.IsResourcePattern {
+		if p.CustomMethodName != "" {
 			// strip the leading slash and the custom method suffix
 			pattern := strings.Split(path, ":")[0][1:]
-			if _, ok := customMethodsByPattern[pattern]; !ok {
-				customMethodsByPattern[pattern] = []*CustomMethod{}
+			if p.IsResourcePattern && p.CustomMethodName == "undelete" && pathItem.Post != nil {
+				undeleteMethodsByPattern[pattern] = &UndeleteMethod{
+					IsLongRunning: pathItem.Post.XAEPLongRunningOperation != nil,
+				}
+				continue
 			}
-			if pathItem.Post != nil {
-				if resp, ok := pathItem.Post.Responses["200"]; ok {
-					lroDetails = pathItem.Post.XAEPLongRunningOperation
-					schema := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
-					responseSchema := &openapi.Schema{}
-					if lroDetails != nil {
-						schema = lroDetails.Response.Schema
-					}
-					if schema != nil {
-						var err error
-						responseSchema, err = api.DereferenceSchema(*schema)
-						if err != nil {
-							return nil, fmt.Errorf("error dereferencing schema %v: %v", schema, err)
-						}
-					}
-					if pathItem.Post.RequestBody == nil {
-						return nil, fmt.Errorf("custom method %q has a POST response, but no request body", p.CustomMethodName)
-					}
-					schema = api.GetSchemaFromRequestBody(*pathItem.Post.RequestBody, openapi.APPLICATION_JSON)
-					requestSchema, err := api.DereferenceSchema(*schema)
-					if err != nil {
-						return nil, fmt.Errorf("error dereferencing schema %q: %v", schema.Ref, err)
-					}
-					customMethodsByPattern[pattern] = append(customMethodsByPattern[pattern], &CustomMethod{
-						Name:          p.CustomMethodName,
-						Method:        "POST",
-						Request:       requestSchema,
-						Response:      responseSchema,
-						IsLongRunning: lroDetails != nil,
-					})
+			if !p.IsResourcePattern {
+				if batchMethods := parseBatchMethod(p.CustomMethodName, pathItem); batchMethods != nil {
+					batchMethodsByPattern[pattern] = append(batchMethodsByPattern[pattern], batchMethods)
+					continue
+				}
+			}
+			customMethods, err := parseCustomMethods(api, p.CustomMethodName, pathItem)
+			if err != nil {
+				return nil, err
+			}
+			if p.IsResourcePattern {
+				customMethodsByPattern[pattern] = append(customMethodsByPattern[pattern], customMethods...)
+			} else {
+				collectionCustomMethodsByPattern[pattern] = append(collectionCustomMethodsByPattern[pattern], customMethods...)
+			}
+		} else if p.IsResourcePattern {
+			// treat it like a collection pattern (update, apply, delete, get)
+			if pathItem.Delete != nil {
+				lroDetails = pathItem.Delete.XAEPLongRunningOperation
+				r.Methods.Delete = &DeleteMethod{
+					IsLongRunning:        lroDetails != nil,
+					SupportsValidateOnly: hasQueryParam(pathItem.Delete, constants.FIELD_VALIDATE_ONLY_NAME),
+					SupportsRequestId:    hasQueryParam(pathItem.Delete, constants.FIELD_REQUEST_ID_NAME),
+				}
+				// a soft delete responds with the deleted resource.
+				if lroDetails != nil {
+					r.Methods.Delete.SupportsSoftDelete = lroDetails.Response.Schema != nil && lroDetails.Response.Schema.Ref != ""
+				} else if resp, ok := pathItem.Delete.Responses["200"]; ok {
+					s := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+					r.Methods.Delete.SupportsSoftDelete = s != nil && s.Ref != ""
 				}
 			}
 			if pathItem.Get != nil {
 				if resp, ok := pathItem.Get.Responses["200"]; ok {
-					lroDetails = pathItem.Get.XAEPLongRunningOperation
-					schema := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
-					responseSchema := &openapi.Schema{}
-					if lroDetails != nil {
-						schema = lroDetails.Response.Schema
+					sRef = api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+					r.Methods.Get = &GetMethod{
+						SupportsReadMask: hasQueryParam(pathItem.Get, constants.FIELD_READ_MASK_NAME),
 					}
-					if schema != nil {
-						var err error
-						responseSchema, err = api.DereferenceSchema(*schema)
-						if err != nil {
-							return nil, fmt.Errorf("error dereferencing schema %v: %v", schema.Ref, err)
-						}
+				}
+			}
+			if pathItem.Patch != nil {
+				lroDetails = pathItem.Patch.XAEPLongRunningOperation
+				if resp, ok := pathItem.Patch.Responses["200"]; ok {
+					sRef = api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+					r.Methods.Update = &UpdateMethod{
+						IsLongRunning:        lroDetails != nil,
+						SupportsValidateOnly: hasQueryParam(pathItem.Patch, constants.FIELD_VALIDATE_ONLY_NAME),
+						SupportsRequestId:    hasQueryParam(pathItem.Patch, constants.FIELD_REQUEST_ID_NAME),
 					}
-					customMethodsByPattern[pattern] = append(customMethodsByPattern[pattern], &CustomMethod{
-						Name:          p.CustomMethodName,
-						Method:        "GET",
-						Response:      responseSchema,
-						IsLongRunning: lroDetails != nil,
-					})
 				}
 			}
-		} else if p.IsResourcePattern {
-			// treat it like a collection pattern (update, delete, get)
-			if pathItem.Delete != nil {
-				lroDetails = pathItem.Delete.XAEPLongRunningOperation
-				r.Methods.Delete = &DeleteMethod{
-					IsLongRunning: lroDetails != nil,
+			if pathItem.Put != nil {
+				lroDetails = pathItem.Put.XAEPLongRunningOperation
+				if resp, ok := pathItem.Put.Responses["200"]; ok {
+					sRef = api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+					r.Methods.Apply = &ApplyMethod{
+						IsLongRunning:        lroDetails != nil,
+						SupportsValidateOnly: hasQueryParam(pathItem.Put, constants.FIELD_VALIDATE_ONLY_NAME),
+						SupportsRequestId:    hasQueryParam(pathItem.Put, constants.FIELD_REQUEST_ID_NAME),
+					}
 				}
 			}
+		} else if isSingletonPath(api, pathItem) {
+			// a singleton is addressed by a path that ends with its
+			// name, rather than an id (get, update).
+			singleton = true
 			if pathItem.Get != nil {
 				if resp, ok := pathItem.Get.Responses["200"]; ok {
 					sRef = api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
-					r.Methods.Get = &GetMethod{}
+					r.Methods.Get = &GetMethod{
+						SupportsReadMask: hasQueryParam(pathItem.Get, constants.FIELD_READ_MASK_NAME),
+					}
 				}
 			}
 			if pathItem.Patch != nil {
@@ -129,7 +158,9 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 				if resp, ok := pathItem.Patch.Responses["200"]; ok {
 					sRef = api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
 					r.Methods.Update = &UpdateMethod{
-						IsLongRunning: lroDetails != nil,
+						IsLongRunning:        lroDetails != nil,
+						SupportsValidateOnly: hasQueryParam(pathItem.Patch, constants.FIELD_VALIDATE_ONLY_NAME),
+						SupportsRequestId:    hasQueryParam(pathItem.Patch, constants.FIELD_REQUEST_ID_NAME),
 					}
 				}
 			}
@@ -151,6 +182,8 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 						r.Methods.Create = &CreateMethod{
 							SupportsUserSettableCreate: supportsUserSettableCreate,
 							IsLongRunning:              lroDetails != nil,
+							SupportsValidateOnly:       hasQueryParam(pathItem.Post, constants.FIELD_VALIDATE_ONLY_NAME),
+							SupportsRequestId:          hasQueryParam(pathItem.Post, constants.FIELD_REQUEST_ID_NAME),
 						}
 					}
 				}
@@ -167,7 +200,14 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 							return nil, fmt.Errorf("error dereferencing schema %q: %v", respSchema.Ref, err)
 						}
 						found := false
-						for _, property := range resolvedSchema.Properties {
+						names := slices.Sorted(maps.Keys(resolvedSchema.Properties))
+						// the results field holds the resources: other
+						// arrays, such as unreachable, must not be mistaken for it.
+						if i := slices.Index(names, constants.FIELD_RESULTS_NAME); i > 0 {
+							names = append([]string{constants.FIELD_RESULTS_NAME}, slices.Delete(names, i, i+1)...)
+						}
+						for _, name := range names {
+							property := resolvedSchema.Properties[name]
 							if property.Type == "array" {
 								sRef = property.Items
 								r.Methods.List = &ListMethod{}
@@ -186,6 +226,13 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 								if param.Name == constants.FIELD_FILTER_NAME {
 									r.Methods.List.SupportsFilter = true
 								}
+								if param.Name == constants.FIELD_READ_MASK_NAME {
+									r.Methods.List.SupportsReadMask = true
+								}
+								if param.Name == constants.FIELD_ORDER_BY_NAME {
+									r.Methods.List.SupportsOrderBy = true
+									r.Methods.List.OrderableFields = param.XAEPOrderableFields
+								}
 							}
 						} else {
 							slog.Warn(fmt.Sprintf("resource %q has a LIST method with a response schema, but the items field is not present or is not an array.", path))
@@ -207,13 +254,13 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 			}
 			singular := cases.PascalToSnakeCase(key)
 			pattern := strings.Split(path, "/")[1:]
-			if !p.IsResourcePattern {
+			if !p.IsResourcePattern && !singleton {
 				// deduplicate the singular, if applicable
 				finalSingular := singular
 				parent := ""
 				if len(pattern) >= 3 {
-					parent = pattern[len(pattern)-3]
-					parent = parent[0 : len(parent)-1] // strip curly surrounding
+					// the parent collection, e.g. "publishers" or "book-editions".
+					parent = cases.KebabToSnakeCase(cases.Singularize(pattern[len(pattern)-3]))
 					if strings.HasPrefix(singular, parent) {
 						finalSingular = strings.TrimPrefix(singular, parent+"_")
 					}
@@ -224,6 +271,12 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 			if err != nil {
 				return nil, fmt.Errorf("error populating resource %q: %v", r.Singular, err)
 			}
+			if singleton {
+				r2.Singleton = true
+			}
+			if hasIfMatchHeader(pathItem.Patch, pathItem.Put, pathItem.Delete) {
+				r2.SupportsEtag = true
+			}
 			foldResourceMethods(&r, r2)
 		}
 	}
@@ -233,16 +286,53 @@ func GetAPI(api *openapi.OpenAPI, serverURL, pathPrefix string) (*API, error) {
 	// we also have to do this by longest pattern match - this helps account for situations where
 	// the custom method doesn't match the resource pattern exactly with things like deduping.
 	for pattern, customMethods := range customMethodsByPattern {
-		found := false
-		for _, r := range resourceBySingular {
-			if r.GetPattern() == pattern {
-				r.CustomMethods = customMethods
-				found = true
-				break
-			}
-		}
-		if !found {
+		r := findResourceByPattern(resourceBySingular, pattern, (*Resource).GetPatterns)
+		if r == nil {
 			slog.Debug(fmt.Sprintf("custom methods with pattern %q have no resource associated with it", pattern))
+			continue
+		}
+		r.CustomMethods = mergeCustomMethods(r.CustomMethods, customMethods)
+	}
+	for pattern, customMethods := range collectionCustomMethodsByPattern {
+		// the path of a singleton looks like a collection, but its custom
+		// methods operate on the resource.
+		if r := findResourceByPattern(resourceBySingular, pattern, (*Resource).GetPatterns); r != nil && r.Singleton {
+			r.CustomMethods = mergeCustomMethods(r.CustomMethods, customMethods)
+			continue
+		}
+		r := findResourceByPattern(resourceBySingular, pattern, (*Resource).collectionPatterns)
+		if r == nil {
+			slog.Debug(fmt.Sprintf("collection custom methods with pattern %q have no resource associated with it", pattern))
+			continue
+		}
+		r.CollectionCustomMethods = mergeCustomMethods(r.CollectionCustomMethods, customMethods)
+	}
+	for pattern, batchMethods := range batchMethodsByPattern {
+		r := findResourceByPattern(resourceBySingular, pattern, (*Resource).collectionPatterns)
+		if r == nil {
+			slog.Debug(fmt.Sprintf("batch methods with pattern %q have no resource associated with it", pattern))
+			continue
+		}
+		for _, m := range batchMethods {
+			foldResourceMethods(&Resource{Methods: *m}, r)
+		}
+	}
+	for pattern, undelete := range undeleteMethodsByPattern {
+		r := findResourceByPattern(resourceBySingular, pattern, (*Resource).GetPatterns)
+		if r == nil {
+			slog.Debug(fmt.Sprintf("undelete method with pattern %q has no resource associated with it", pattern))
+			continue
+		}
+		r.Methods.Undelete = undelete
+	}
+	for pattern, revisionMethods := range revisionMethodsByPattern {
+		r := findResourceByPattern(resourceBySingular, pattern, (*Resource).GetPatterns)
+		if r == nil {
+			slog.Debug(fmt.Sprintf("revision methods with pattern %q have no resource associated with it", pattern))
+			continue
+		}
+		for _, m := range revisionMethods {
+			foldResourceMethods(&Resource{Methods: *m}, r)
 		}
 	}
 	if serverURL == "" {
@@ -313,12 +403,52 @@ func getPatternInfo(path string) *PatternInfo {
 	}
 }
 
+// isSingletonPath returns true if a path that does not end with an id
+// addresses a singleton resource (aep.dev/156), rather than a
+// collection. Collections are never updated, and are listed with a
+// response wrapping the resources in an array.
+func isSingletonPath(api *openapi.OpenAPI, pathItem *openapi.PathItem) bool {
+	if pathItem.Patch != nil {
+		return true
+	}
+	if pathItem.Get == nil || pathItem.Post != nil {
+		return false
+	}
+	resp, ok := pathItem.Get.Responses["200"]
+	if !ok {
+		return false
+	}
+	schema := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+	if schema == nil || schema.Ref == "" {
+		return false
+	}
+	resolved, err := api.DereferenceSchema(*schema)
+	if err != nil {
+		return false
+	}
+	if resolved.XAEPResource != nil {
+		return true
+	}
+	for _, property := range resolved.Properties {
+		if property.Type == "array" {
+			return false
+		}
+	}
+	return true
+}
+
 // getOrPopulateResource populates the resource via a variety of means:
 // - if the resource already exists in the map, it returns it
 // - if the schema has the x-aep-resource annotation, it parses the resource
 // - otherwise, it attempts to infer the resource from the schema and name.
+//
+// A resource that is inferred from multiple paths (e.g. one under
+// publishers and one under authors) collects a pattern for each.
 func getOrPopulateResource(singular string, pattern []string, s *openapi.Schema, resourceBySingular map[string]*Resource, api *openapi.OpenAPI) (*Resource, error) {
 	if r, ok := resourceBySingular[singular]; ok {
+		if s.XAEPResource == nil && len(pattern) > 0 && !r.hasEquivalentPattern(pattern) {
+			r.Patterns = append(r.Patterns, strings.Join(pattern, "/"))
+		}
 		return r, nil
 	}
 	var r *Resource
@@ -336,28 +466,37 @@ func getOrPopulateResource(singular string, pattern []string, s *openapi.Schema,
 				return nil, fmt.Errorf("error parsing resource %q parent %q: %v", singular, parentSingular, err)
 			}
 			parents = append(parents, parentResource)
-			parentResource.Children = append(parentResource.Children, r)
 		}
-		patternElems := strings.Split(strings.TrimPrefix(s.XAEPResource.Patterns[0], "/"), "/")
+		patterns := []string{}
+		for _, p := range s.XAEPResource.Patterns {
+			patterns = append(patterns, strings.TrimPrefix(p, "/"))
+		}
 		r = &Resource{
 			Singular:        s.XAEPResource.Singular,
 			Plural:          s.XAEPResource.Plural,
 			Parents:         s.XAEPResource.Parents,
 			parentResources: parents,
 			Children:        []*Resource{},
-			patternElems:    patternElems,
-			Schema:          s,
+			Patterns:        patterns,
+			// the patterns of a singleton end with its name, rather than an id.
+			Singleton: len(patterns) > 0 && len(strings.Split(patterns[0], "/"))%2 == 1,
+			Schema:    s,
+		}
+		for _, parentResource := range parents {
+			parentResource.Children = append(parentResource.Children, r)
 		}
 	} else {
 		// best effort otherwise
 		r = &Resource{
 			Schema:          s,
-			patternElems:    pattern,
 			Singular:        singular,
 			Parents:         []string{},
 			parentResources: []*Resource{},
 			Children:        []*Resource{},
-			Plural:          plural(singular),
+			Plural:          cases.Pluralize(singular),
+		}
+		if len(pattern) > 0 {
+			r.Patterns = []string{strings.Join(pattern, "/")}
 		}
 	}
 	// update the resource map
@@ -365,6 +504,211 @@ func getOrPopulateResource(singular string, pattern []string, s *openapi.Schema,
 	return r, nil
 }
 
+// parseCustomMethods returns the custom methods defined by the
+// operations of a custom method path (e.g. /books/{book_id}:archive).
+func parseCustomMethods(api *openapi.OpenAPI, name string, pathItem *openapi.PathItem) ([]*CustomMethod, error) {
+	customMethods := []*CustomMethod{}
+	if pathItem.Post != nil {
+		if resp, ok := pathItem.Post.Responses["200"]; ok {
+			lroDetails := pathItem.Post.XAEPLongRunningOperation
+			schema := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+			responseSchema := &openapi.Schema{}
+			if lroDetails != nil {
+				schema = lroDetails.Response.Schema
+			}
+			if schema != nil {
+				var err error
+				responseSchema, err = api.DereferenceSchema(*schema)
+				if err != nil {
+					return nil, fmt.Errorf("error dereferencing schema %v: %v", schema, err)
+				}
+			}
+			if pathItem.Post.RequestBody == nil {
+				return nil, fmt.Errorf("custom method %q has a POST response, but no request body", name)
+			}
+			schema = api.GetSchemaFromRequestBody(*pathItem.Post.RequestBody, openapi.APPLICATION_JSON)
+			requestSchema, err := api.DereferenceSchema(*schema)
+			if err != nil {
+				return nil, fmt.Errorf("error dereferencing schema %q: %v", schema.Ref, err)
+			}
+			customMethods = append(customMethods, &CustomMethod{
+				Name:          name,
+				Method:        "POST",
+				Request:       requestSchema,
+				Response:      responseSchema,
+				IsLongRunning: lroDetails != nil,
+			})
+		}
+	}
+	if pathItem.Get != nil {
+		if resp, ok := pathItem.Get.Responses["200"]; ok {
+			lroDetails := pathItem.Get.XAEPLongRunningOperation
+			schema := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+			responseSchema := &openapi.Schema{}
+			if lroDetails != nil {
+				schema = lroDetails.Response.Schema
+			}
+			if schema != nil {
+				var err error
+				responseSchema, err = api.DereferenceSchema(*schema)
+				if err != nil {
+					return nil, fmt.Errorf("error dereferencing schema %v: %v", schema.Ref, err)
+				}
+			}
+			customMethods = append(customMethods, &CustomMethod{
+				Name:          name,
+				Method:        "GET",
+				Response:      responseSchema,
+				IsLongRunning: lroDetails != nil,
+			})
+		}
+	}
+	return customMethods, nil
+}
+
+// parseBatchMethod returns the batch method defined by a collection
+// custom method path (e.g. /books:batchGet), or nil if it does not
+// define one.
+func parseBatchMethod(name string, pathItem *openapi.PathItem) *Methods {
+	m := &Methods{}
+	switch {
+	case name == "batchGet" && pathItem.Get != nil:
+		m.BatchGet = &BatchGetMethod{}
+	case name == "batchCreate" && pathItem.Post != nil:
+		m.BatchCreate = &BatchCreateMethod{
+			IsLongRunning: pathItem.Post.XAEPLongRunningOperation != nil,
+		}
+	case name == "batchUpdate" && pathItem.Post != nil:
+		m.BatchUpdate = &BatchUpdateMethod{
+			IsLongRunning: pathItem.Post.XAEPLongRunningOperation != nil,
+		}
+	case name == "batchDelete" && pathItem.Post != nil:
+		m.BatchDelete = &BatchDeleteMethod{
+			IsLongRunning: pathItem.Post.XAEPLongRunningOperation != nil,
+		}
+	default:
+		return nil
+	}
+	return m
+}
+
+// hasQueryParam returns true if the operation accepts the query
+// parameter name.
+func hasQueryParam(op *openapi.Operation, name string) bool {
+	return slices.ContainsFunc(op.Parameters, func(param openapi.Parameter) bool {
+		return param.In == "query" && param.Name == name
+	})
+}
+
+// hasIfMatchHeader returns true if any of the operations accepts an
+// If-Match header, which marks a resource with an etag (aep.dev/154).
+func hasIfMatchHeader(operations ...*openapi.Operation) bool {
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+		for _, param := range op.Parameters {
+			if param.In == "header" && strings.EqualFold(param.Name, constants.HEADER_IF_MATCH) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseRevisionMethod returns the resource pattern and the revision
+// method (aep.dev/162) defined by a path, such as
+// /books/{book_id}@{revision_id} or /books/{book_id}:commit, or nil if
+// the path does not define one.
+func parseRevisionMethod(api *openapi.OpenAPI, path string, pathItem *openapi.PathItem) (string, *Methods) {
+	path, customMethodName, _ := strings.Cut(path, ":")
+	m := &Methods{}
+	resourcePath, _, isRevision := strings.Cut(path, "@")
+	switch {
+	case isRevision && customMethodName == "" && pathItem.Get != nil:
+		m.GetRevision = &GetRevisionMethod{}
+	case isRevision && customMethodName == "tagRevision" && pathItem.Post != nil:
+		m.TagRevision = &TagRevisionMethod{}
+	case isRevision:
+		return "", nil
+	case customMethodName == "commit" && pathItem.Post != nil:
+		m.Commit = &CommitMethod{
+			IsLongRunning: pathItem.Post.XAEPLongRunningOperation != nil,
+		}
+	case customMethodName == "rollback" && pathItem.Post != nil:
+		m.Rollback = &RollbackMethod{
+			IsLongRunning: pathItem.Post.XAEPLongRunningOperation != nil,
+		}
+	case customMethodName == "" && strings.HasSuffix(path, "/revisions") && isRevisionsList(api, path, pathItem):
+		resourcePath = strings.TrimSuffix(path, "/revisions")
+		m.ListRevisions = &ListRevisionsMethod{}
+	default:
+		return "", nil
+	}
+	if p := getPatternInfo(resourcePath); p == nil || !p.IsResourcePattern {
+		return "", nil
+	}
+	return strings.TrimPrefix(resourcePath, "/"), m
+}
+
+// isRevisionsList returns true if path lists the revisions of a
+// resource, rather than a child collection named revisions: the
+// revisions of a resource have the schema of the resource itself.
+func isRevisionsList(api *openapi.OpenAPI, path string, pathItem *openapi.PathItem) bool {
+	if pathItem.Get == nil {
+		return false
+	}
+	resp, ok := pathItem.Get.Responses["200"]
+	if !ok {
+		return false
+	}
+	respSchema := api.GetSchemaFromResponse(resp, openapi.APPLICATION_JSON)
+	if respSchema == nil {
+		return false
+	}
+	resolvedSchema, err := api.DereferenceSchema(*respSchema)
+	if err != nil {
+		return false
+	}
+	results, ok := resolvedSchema.Properties[constants.FIELD_RESULTS_NAME]
+	if !ok || results.Items == nil || results.Items.Ref == "" {
+		return false
+	}
+	pattern := strings.Split(path, "/")
+	if len(pattern) < 4 {
+		return false
+	}
+	// e.g. "book_edition" for /publishers/{publisher_id}/book-editions/{book_edition_id}/revisions
+	parts := strings.Split(results.Items.Ref, "/")
+	singular := cases.KebabToSnakeCase(cases.PascalToSnakeCase(parts[len(parts)-1]))
+	return strings.HasSuffix(singular, cases.KebabToSnakeCase(cases.Singularize(pattern[len(pattern)-3])))
+}
+
+// findResourceByPattern returns the resource for which patternsOf
+// includes pattern, or nil if there is none.
+func findResourceByPattern(resourceBySingular map[string]*Resource, pattern string, patternsOf func(*Resource) []string) *Resource {
+	for _, r := range resourceBySingular {
+		if slices.Contains(patternsOf(r), pattern) {
+			return r
+		}
+	}
+	return nil
+}
+
+// mergeCustomMethods adds the custom methods that are not yet present
+// in existing. A resource with multiple patterns exposes the same
+// custom methods under each of them.
+func mergeCustomMethods(existing, customMethods []*CustomMethod) []*CustomMethod {
+	for _, cm := range customMethods {
+		if !slices.ContainsFunc(existing, func(e *CustomMethod) bool {
+			return e.Name == cm.Name
+		}) {
+			existing = append(existing, cm)
+		}
+	}
+	return existing
+}
+
 func foldResourceMethods(from, into *Resource) {
 	if from.Methods.Get != nil {
 		into.Methods.Get = from.Methods.Get
@@ -381,6 +725,39 @@ func foldResourceMethods(from, into *Resource) {
 	if from.Methods.Delete != nil {
 		into.Methods.Delete = from.Methods.Delete
 	}
+	if from.Methods.Apply != nil {
+		into.Methods.Apply = from.Methods.Apply
+	}
+	if from.Methods.Undelete != nil {
+		into.Methods.Undelete = from.Methods.Undelete
+	}
+	if from.Methods.ListRevisions != nil {
+		into.Methods.ListRevisions = from.Methods.ListRevisions
+	}
+	if from.Methods.GetRevision != nil {
+		into.Methods.GetRevision = from.Methods.GetRevision
+	}
+	if from.Methods.Commit != nil {
+		into.Methods.Commit = from.Methods.Commit
+	}
+	if from.Methods.Rollback != nil {
+		into.Methods.Rollback = from.Methods.Rollback
+	}
+	if from.Methods.TagRevision != nil {
+		into.Methods.TagRevision = from.Methods.TagRevision
+	}
+	if from.Methods.BatchGet != nil {
+		into.Methods.BatchGet = from.Methods.BatchGet
+	}
+	if from.Methods.BatchCreate != nil {
+		into.Methods.BatchCreate = from.Methods.BatchCreate
+	}
+	if from.Methods.BatchUpdate != nil {
+		into.Methods.BatchUpdate = from.Methods.BatchUpdate
+	}
+	if from.Methods.BatchDelete != nil {
+		into.Methods.BatchDelete = from.Methods.BatchDelete
+	}
 }
 
 func getContact(contact openapi.Contact) *Contact {
@@ -393,10 +770,3 @@ func getContact(contact openapi.Contact) *Contact {
 	}
 	return nil
 }
-
-// plural returns the plural form of a singular resource name
-// This is a simple implementation that just adds 's' to the end
-// of the singular form, which works for most cases.
-func plural(singular string) string {
-	return singular + "s"
-}
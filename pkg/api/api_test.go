@@ -164,6 +164,21 @@ var basicOpenAPI = &openapi.OpenAPI{
 	},
 }
 
+// schemaResponse returns a 200 response with a JSON body of the referenced schema.
+func schemaResponse(ref string) map[string]openapi.Response {
+	return map[string]openapi.Response{
+		"200": {
+			Content: map[string]openapi.MediaType{
+				"application/json": {
+					Schema: &openapi.Schema{
+						Ref: ref,
+					},
+				},
+			},
+		},
+	}
+}
+
 func TestGetAPI(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -521,6 +536,26 @@ func TestGetAPI(t *testing.T) {
 								},
 							},
 						},
+						Put: &openapi.Operation{
+							XAEPLongRunningOperation: &openapi.XAEPLongRunningOperation{
+								Response: openapi.XAEPLongRunningOperationResponse{
+									Schema: &openapi.Schema{
+										Ref: "#/components/schemas/Widget",
+									},
+								},
+							},
+							Responses: map[string]openapi.Response{
+								"200": {
+									Content: map[string]openapi.MediaType{
+										"application/json": {
+											Schema: &openapi.Schema{
+												Ref: AEP_OPERATION_REF,
+											},
+										},
+									},
+								},
+							},
+						},
 					},
 					"/widgets/{widget_id}:customOp": {
 						Post: &openapi.Operation{
@@ -577,6 +612,10 @@ func TestGetAPI(t *testing.T) {
 				assert.NotNil(t, widget.Methods.Update, "should have UPDATE method")
 				assert.True(t, widget.Methods.Update.IsLongRunning, "UPDATE method should be marked as long running")
 
+				// Check apply method is marked as long running
+				assert.NotNil(t, widget.Methods.Apply, "should have APPLY method")
+				assert.True(t, widget.Methods.Apply.IsLongRunning, "APPLY method should be marked as long running")
+
 				// Check delete method is marked as long running
 				assert.NotNil(t, widget.Methods.Delete, "should have DELETE method")
 				assert.True(t, widget.Methods.Delete.IsLongRunning, "DELETE method should be marked as long running")
@@ -588,6 +627,112 @@ func TestGetAPI(t *testing.T) {
 				assert.True(t, customOp.IsLongRunning, "customOp method should be marked as long running")
 			},
 		},
+		{
+			name: "resource with multiple x-aep-resource patterns",
+			api: &openapi.OpenAPI{
+				OpenAPI: "3.1.0",
+				Servers: []openapi.Server{{URL: "https://api.example.com"}},
+				Paths: map[string]*openapi.PathItem{
+					"/publishers/{publisher_id}/books/{book_id}": {
+						Get: &openapi.Operation{Responses: schemaResponse("#/components/schemas/book")},
+					},
+					"/authors/{author_id}/books/{book_id}": {
+						Get: &openapi.Operation{Responses: schemaResponse("#/components/schemas/book")},
+					},
+					"/authors/{author_id}/books/{book_id}:publish": {
+						Get: &openapi.Operation{Responses: schemaResponse("#/components/schemas/book")},
+					},
+				},
+				Components: openapi.Components{
+					Schemas: map[string]openapi.Schema{
+						"book": {
+							Type: "object",
+							XAEPResource: &openapi.XAEPResource{
+								Singular: "book",
+								Plural:   "books",
+								Patterns: []string{
+									"/publishers/{publisher_id}/books/{book_id}",
+									"/authors/{author_id}/books/{book_id}",
+								},
+							},
+						},
+					},
+				},
+			},
+			validateResult: func(t *testing.T, sd *API) {
+				book, ok := sd.Resources["book"]
+				require.True(t, ok, "book resource should exist")
+				assert.Equal(t, []string{
+					"publishers/{publisher_id}/books/{book_id}",
+					"authors/{author_id}/books/{book_id}",
+				}, book.GetPatterns())
+				assert.Equal(t, "publishers/{publisher_id}/books/{book_id}", book.GetPattern())
+				require.Len(t, book.CustomMethods, 1, "custom methods on any pattern should be found")
+				assert.Equal(t, "publish", book.CustomMethods[0].Name)
+			},
+		},
+		{
+			name: "resource inferred from multiple paths",
+			api: &openapi.OpenAPI{
+				OpenAPI: "3.1.0",
+				Servers: []openapi.Server{{URL: "https://api.example.com"}},
+				Paths: map[string]*openapi.PathItem{
+					"/publishers/{publisher_id}/books/{book_id}": {
+						Get: &openapi.Operation{Responses: schemaResponse("#/components/schemas/Book")},
+					},
+					"/authors/{author_id}/books/{book_id}": {
+						Get: &openapi.Operation{Responses: schemaResponse("#/components/schemas/Book")},
+					},
+					"/authors/{author_id}/books/{id}": {
+						Delete: &openapi.Operation{},
+						Get:    &openapi.Operation{Responses: schemaResponse("#/components/schemas/Book")},
+					},
+				},
+				Components: openapi.Components{
+					Schemas: map[string]openapi.Schema{
+						"Book": {Type: "object"},
+					},
+				},
+			},
+			validateResult: func(t *testing.T, sd *API) {
+				book, ok := sd.Resources["book"]
+				require.True(t, ok, "book resource should exist")
+				assert.Equal(t, []string{
+					"authors/{author_id}/books/{book_id}",
+					"publishers/{publisher_id}/books/{book_id}",
+				}, book.GetPatterns())
+			},
+		},
+		{
+			name: "inferred resource names are inflected",
+			api: &openapi.OpenAPI{
+				OpenAPI: "3.1.0",
+				Servers: []openapi.Server{{URL: "https://api.example.com"}},
+				Paths: map[string]*openapi.PathItem{
+					"/policies/{policy_id}": {
+						Get: &openapi.Operation{Responses: schemaResponse("#/components/schemas/Policy")},
+					},
+					"/policies/{policy_id}/statuses": {
+						Post: &openapi.Operation{Responses: schemaResponse("#/components/schemas/PolicyStatus")},
+					},
+				},
+				Components: openapi.Components{
+					Schemas: map[string]openapi.Schema{
+						"Policy":       {Type: "object"},
+						"PolicyStatus": {Type: "object"},
+					},
+				},
+			},
+			validateResult: func(t *testing.T, sd *API) {
+				policy, ok := sd.Resources["policy"]
+				require.True(t, ok, "policy resource should exist")
+				assert.Equal(t, "policies", policy.Plural)
+				status, ok := sd.Resources["policy_status"]
+				require.True(t, ok, "policy_status resource should exist")
+				assert.Equal(t, "policy_statuses", status.Plural)
+				assert.Equal(t, []string{"policies/{policy_id}/statuses/{status_id}"}, status.GetPatterns())
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -679,3 +824,64 @@ func TestLoadFromJsonBookstore(t *testing.T) {
 	assert.Equal(t, "string", bookEditionResource.Schema.Properties["displayname"].Type)
 	assert.NotNil(t, bookEditionResource.Methods.List, "'book-edition' should have List method")
 }
+
+func TestLoadFromFileBookstore(t *testing.T) {
+	// Construct the path relative to the test file's location
+	yamlPath := filepath.Join("..", "..", "examples", "resource-definitions", "bookstore.yaml")
+
+	apiResult, err := LoadAPIFromFile(yamlPath)
+	require.NoError(t, err, "Failed to load API from bookstore.yaml")
+
+	// Assert basic fields that might match (like Name, ServerURL, Contact)
+	assert.Equal(t, "bookstore.example.com", apiResult.Name, "API Name should be populated if field names match")
+	assert.Equal(t, "http://localhost:8081", apiResult.ServerURL, "API ServerURL should be populated based on json tag")
+	if assert.NotNil(t, apiResult.Contact, "Contact might be populated if fields match") {
+		assert.Equal(t, "API support", apiResult.Contact.Name)
+		assert.Equal(t, "aepsupport@aep.dev", apiResult.Contact.Email)
+	}
+
+	// Assert that Resources map IS populated correctly
+	assert.NotEmpty(t, apiResult.Resources, "Resources map should be populated")
+	assert.Contains(t, apiResult.Resources, "publisher", "Resources map should contain 'publisher'")
+	assert.Contains(t, apiResult.Resources, "book", "Resources map should contain 'book'")
+	assert.Contains(t, apiResult.Resources, "book-edition", "Resources map should contain 'book-edition'")
+	assert.Contains(t, apiResult.Resources, "isbn", "Resources map should contain 'isbn'")
+
+	// Check some details of a resource
+	publisherResource := apiResult.Resources["publisher"]
+	assert.NotNil(t, publisherResource, "'publisher' resource should not be nil")
+	assert.Equal(t, "publisher", publisherResource.Singular)
+	assert.Equal(t, "publishers", publisherResource.Plural)
+	assert.NotNil(t, publisherResource.Schema, "'publisher' resource schema should not be nil")
+	assert.Equal(t, "object", publisherResource.Schema.Type)
+	assert.Contains(t, publisherResource.Schema.Properties, "description")
+	assert.Equal(t, "string", publisherResource.Schema.Properties["description"].Type)
+	assert.NotNil(t, publisherResource.Methods.Get, "'publisher' should have Get method, declared as read")
+	assert.NotNil(t, publisherResource.Methods.List, "'publisher' should have List method")
+	assert.True(t, publisherResource.Methods.List.SupportsFilter)
+
+	// Check book resource details, including custom method
+	bookResource := apiResult.Resources["book"]
+	assert.NotNil(t, bookResource, "'book' resource should not be nil")
+	assert.Equal(t, "book", bookResource.Singular)
+	assert.Equal(t, "books", bookResource.Plural)
+	assert.NotNil(t, bookResource.Schema, "'book' resource schema should not be nil")
+	assert.Contains(t, bookResource.Schema.Properties, "isbn")
+	assert.Equal(t, "array", bookResource.Schema.Properties["isbn"].Type)
+	assert.NotNil(t, bookResource.Methods.List, "'book' should have List method")
+	assert.True(t, bookResource.Methods.List.HasUnreachableResources)
+	assert.True(t, bookResource.Methods.Create.SupportsUserSettableCreate)
+	assert.Len(t, bookResource.CustomMethods, 1, "'book' should have 1 custom method")
+	assert.Equal(t, "archive", bookResource.CustomMethods[0].Name)
+
+	// Check book-edition resource details
+	bookEditionResource := apiResult.Resources["book-edition"]
+	assert.NotNil(t, bookEditionResource, "'book-edition' resource should not be nil")
+	assert.Equal(t, "book-edition", bookEditionResource.Singular)
+	assert.Equal(t, "book-editions", bookEditionResource.Plural)
+	assert.NotNil(t, bookEditionResource.Schema, "'book-edition' resource schema should not be nil")
+	assert.Equal(t, "object", bookEditionResource.Schema.Type)
+	assert.Contains(t, bookEditionResource.Schema.Properties, "displayname")
+	assert.Equal(t, "string", bookEditionResource.Schema.Properties["displayname"].Type)
+	assert.NotNil(t, bookEditionResource.Methods.List, "'book-edition' should have List method")
+}
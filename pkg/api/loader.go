@@ -3,19 +3,93 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/constants"
 	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/aep-dev/aep-lib-go/pkg/yamlutil"
 )
 
 var singularPluralRegex = regexp.MustCompile("^[a-z][a-z0-9_-]*[a-z0-9]$")
 
-func LoadAPIFromJson(data []byte) (*API, error) {
+// LoadOption configures how an API definition is decoded.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	strict bool
+}
+
+// WithStrictDecoding rejects keys in the definition that do not map to
+// a known field, reporting the full path of each one (e.g.
+// "resources.book.methods.reed"). All problems in the definition are
+// returned at once, as DecodeErrors.
+//
+// Resource and custom method schemas are JSON schemas, which are open
+// ended: their keys are not checked.
+func WithStrictDecoding() LoadOption {
+	return func(o *loadOptions) {
+		o.strict = true
+	}
+}
+
+func LoadAPIFromJson(data []byte, opts ...LoadOption) (*API, error) {
+	return loadAPI(data, nil, opts)
+}
+
+// LoadAPIFromYAML loads an API from a YAML resource definition.
+//
+// Anchors and merge keys are resolved, and multiple documents are
+// merged together, so large definitions can be split into several
+// documents (e.g. one per resource). Decoding errors include the
+// line and column of the offending value.
+func LoadAPIFromYAML(data []byte, opts ...LoadOption) (*API, error) {
+	jsonData, sm, err := yamlutil.ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling API: %w", err)
+	}
+	return loadAPI(jsonData, sm, opts)
+}
+
+// LoadAPIFromFile loads an API from a resource definition file. The
+// format is chosen by the file extension (.json, .yaml or .yml), and
+// sniffed from the content otherwise.
+func LoadAPIFromFile(path string, opts ...LoadOption) (*API, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading API file %q: %w", path, err)
+	}
+	switch filepath.Ext(path) {
+	case ".json":
+		return LoadAPIFromJson(data, opts...)
+	case ".yaml", ".yml":
+		return LoadAPIFromYAML(data, opts...)
+	}
+	if yamlutil.IsJSON(data) {
+		return LoadAPIFromJson(data, opts...)
+	}
+	return LoadAPIFromYAML(data, opts...)
+}
+
+// loadAPI decodes a JSON API definition. sm is used to annotate
+// errors with their source position, and may be nil.
+func loadAPI(data []byte, sm yamlutil.SourceMap, opts []LoadOption) (*API, error) {
+	o := loadOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.strict {
+		if err := checkStrict(data, sm); err != nil {
+			return nil, fmt.Errorf("error unmarshalling API: %w", err)
+		}
+	}
 	api := &API{}
 	err := json.Unmarshal(data, api)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling API: %v", err)
+		return nil, fmt.Errorf("error unmarshalling API: %w", sm.WrapError(err))
 	}
 	err = AddImplicitFieldsAndValidate(api)
 	if err != nil {
@@ -38,6 +112,53 @@ func AddImplicitFieldsAndValidate(api *API) error {
 		if !singularPluralRegex.MatchString(r.Plural) {
 			return fmt.Errorf("plural resource name %s does not match the regex %s", r.Plural, singularPluralRegex.String())
 		}
+		for _, p := range r.Patterns {
+			info := getPatternInfo("/" + strings.TrimPrefix(p, "/"))
+			if info == nil || !info.IsResourcePattern || info.CustomMethodName != "" {
+				// the pattern of a singleton ends with its name rather
+				// than an id, and is nested under a parent.
+				isSingletonPattern := info != nil && info.CustomMethodName == "" && len(strings.Split(p, "/")) >= 3
+				if !r.Singleton || !isSingletonPattern {
+					return fmt.Errorf("pattern %q of resource %s is not a valid resource pattern", p, r.Singular)
+				}
+			} else if r.Singleton {
+				return fmt.Errorf("pattern %q of singleton resource %s must not end with an id", p, r.Singular)
+			}
+		}
+		if r.Singleton {
+			if len(r.Parents) == 0 && len(r.Patterns) == 0 {
+				return fmt.Errorf("singleton resource %s must have a parent", r.Singular)
+			}
+			unsupported := []struct {
+				name    string
+				defined bool
+			}{
+				{"create", r.Methods.Create != nil},
+				{"list", r.Methods.List != nil},
+				{"delete", r.Methods.Delete != nil},
+				{"apply", r.Methods.Apply != nil},
+				{"batch_get", r.Methods.BatchGet != nil},
+				{"batch_create", r.Methods.BatchCreate != nil},
+				{"batch_update", r.Methods.BatchUpdate != nil},
+				{"batch_delete", r.Methods.BatchDelete != nil},
+			}
+			for _, m := range unsupported {
+				if m.defined {
+					return fmt.Errorf("singleton resource %s does not support the %s method", r.Singular, m.name)
+				}
+			}
+			if len(r.CollectionCustomMethods) > 0 {
+				return fmt.Errorf("singleton resource %s does not support collection custom methods", r.Singular)
+			}
+		}
+		// batch create and update requests repeat the requests of the
+		// corresponding standard methods.
+		if r.Methods.BatchCreate != nil && r.Methods.Create == nil {
+			return fmt.Errorf("resource %s has a batch_create method, but no create method", r.Singular)
+		}
+		if r.Methods.BatchUpdate != nil && r.Methods.Update == nil {
+			return fmt.Errorf("resource %s has a batch_update method, but no update method", r.Singular)
+		}
 		r.API = api
 		if r.Schema.Properties == nil {
 			r.Schema.Properties = make(map[string]openapi.Schema)
@@ -50,10 +171,84 @@ func AddImplicitFieldsAndValidate(api *API) error {
 			},
 			ReadOnly: true,
 		}
+		if r.SupportsEtag {
+			r.Schema.Properties[constants.FIELD_ETAG_NAME] = openapi.Schema{
+				Type:        "string",
+				Description: "The etag of the resource, which changes whenever the resource changes.",
+				XAEPField: &openapi.XAEPField{
+					FieldNumber: constants.FIELD_ETAG_NUMBER,
+				},
+			}
+		}
+		if r.Methods.Undelete != nil && !r.SupportsSoftDelete() {
+			return fmt.Errorf("resource %s has an undelete method, but does not support soft delete", r.Singular)
+		}
+		if r.SupportsSoftDelete() {
+			r.Schema.Properties[constants.FIELD_DELETE_TIME_NAME] = openapi.Schema{
+				Type:        "string",
+				Format:      "date-time",
+				Description: "The time the resource was soft-deleted, if it was.",
+				XAEPField: &openapi.XAEPField{
+					FieldNumber: constants.FIELD_DELETE_TIME_NUMBER,
+				},
+				ReadOnly: true,
+			}
+			r.Schema.Properties[constants.FIELD_EXPIRE_TIME_NAME] = openapi.Schema{
+				Type:        "string",
+				Format:      "date-time",
+				Description: "The time after which a soft-deleted resource is purged.",
+				XAEPField: &openapi.XAEPField{
+					FieldNumber: constants.FIELD_EXPIRE_TIME_NUMBER,
+				},
+				ReadOnly: true,
+			}
+		}
+		if r.SupportsRevisions() {
+			// revisions are snapshots of the resource, which are
+			// retrieved with the schema of the resource.
+			if r.Methods.Get == nil {
+				return fmt.Errorf("resource %s has revision methods, but no get method", r.Singular)
+			}
+			r.Schema.Properties[constants.FIELD_REVISION_ID_NAME] = openapi.Schema{
+				Type:        "string",
+				Description: "The id of the revision of the resource.",
+				XAEPField: &openapi.XAEPField{
+					FieldNumber: constants.FIELD_REVISION_ID_NUMBER,
+				},
+				ReadOnly: true,
+			}
+			r.Schema.Properties[constants.FIELD_REVISION_CREATE_TIME_NAME] = openapi.Schema{
+				Type:        "string",
+				Format:      "date-time",
+				Description: "The time the revision of the resource was created.",
+				XAEPField: &openapi.XAEPField{
+					FieldNumber: constants.FIELD_REVISION_CREATE_TIME_NUMBER,
+				},
+				ReadOnly: true,
+			}
+		}
+		if r.Methods.List != nil && len(r.Methods.List.OrderableFields) > 0 {
+			if !r.Methods.List.SupportsOrderBy {
+				return fmt.Errorf("resource %s has orderable fields, but its list method does not support order_by", r.Singular)
+			}
+			for _, field := range r.Methods.List.OrderableFields {
+				// nested fields are ordered by with a dotted path.
+				name, _, _ := strings.Cut(field, ".")
+				if _, ok := r.Schema.Properties[name]; !ok {
+					return fmt.Errorf("orderable field %q of resource %s is not a field of the resource", field, r.Singular)
+				}
+			}
+		}
+		// rebuild the parent links, so that validating an API more than
+		// once does not duplicate them.
+		r.parentResources = []*Resource{}
+		r.patternElems = nil
 		for _, p := range r.Parents {
 			if parent, ok := api.Resources[p]; ok {
 				r.parentResources = append(r.parentResources, parent)
-				parent.Children = append(parent.Children, r)
+				if !slices.Contains(parent.Children, r) {
+					parent.Children = append(parent.Children, r)
+				}
 			} else {
 				return fmt.Errorf("parent resource %s not found for resource %s", p, r.Singular)
 			}
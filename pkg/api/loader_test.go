@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/aep-dev/aep-lib-go/pkg/openapi"
@@ -69,3 +71,174 @@ func TestAddImplicitFieldsAndValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadAPIFromYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr string
+		validate    func(*testing.T, *API)
+	}{
+		{
+			name: "anchors are resolved",
+			input: `
+name: "example.com"
+resources:
+  book: &book
+    singular: "book"
+    plural: "books"
+    schema:
+      type: object
+  tome:
+    <<: *book
+    singular: "tome"
+    plural: "tomes"
+`,
+			validate: func(t *testing.T, api *API) {
+				require.Contains(t, api.Resources, "tome")
+				assert.Equal(t, "tome", api.Resources["tome"].Singular)
+				assert.Equal(t, "object", api.Resources["tome"].Schema.Type)
+			},
+		},
+		{
+			name: "multiple documents are merged",
+			input: `
+name: "example.com"
+resources:
+  book:
+    singular: "book"
+    plural: "books"
+    schema:
+      type: object
+---
+resources:
+  publisher:
+    singular: "publisher"
+    plural: "publishers"
+    schema:
+      type: object
+`,
+			validate: func(t *testing.T, api *API) {
+				assert.Equal(t, "example.com", api.Name)
+				assert.Contains(t, api.Resources, "book")
+				assert.Contains(t, api.Resources, "publisher")
+			},
+		},
+		{
+			name: "type errors report the yaml position",
+			input: `
+name: "example.com"
+resources:
+  book:
+    singular: "book"
+    plural: "books"
+    parents: "publisher"
+`,
+			expectedErr: "line 7, column 14 (resources.book.parents)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, err := LoadAPIFromYAML([]byte(tt.input))
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.validate != nil {
+				tt.validate(t, api)
+			}
+		})
+	}
+}
+
+func TestMethodAliases(t *testing.T) {
+	api, err := LoadAPIFromJson([]byte(`{
+		"name": "example.com",
+		"resources": {
+			"book": {
+				"singular": "book",
+				"plural": "books",
+				"schema": {"type": "object"},
+				"methods": {"read": {}}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.NotNil(t, api.Resources["book"].Methods.Get, "read should be decoded as get")
+
+	_, err = LoadAPIFromJson([]byte(`{
+		"resources": {
+			"book": {
+				"singular": "book",
+				"plural": "books",
+				"schema": {"type": "object"},
+				"methods": {"read": {}, "get": {}}
+			}
+		}
+	}`))
+	assert.ErrorContains(t, err, `method "get" is defined twice, as "get" and "read"`)
+}
+
+func TestLoadAPIStrict(t *testing.T) {
+	t.Run("bookstore example is valid", func(t *testing.T) {
+		path := filepath.Join("..", "..", "examples", "resource-definitions", "bookstore.yaml")
+		_, err := LoadAPIFromFile(path, WithStrictDecoding())
+		assert.NoError(t, err)
+	})
+
+	t.Run("all problems are reported", func(t *testing.T) {
+		_, err := LoadAPIFromJson([]byte(`{
+			"name": "example.com",
+			"resources": {
+				"book": {
+					"singular": "book",
+					"plural": "books",
+					"parents": "publisher",
+					"schema": {"type": "object", "x-custom": true},
+					"methods": {
+						"reed": {},
+						"create": {"is_long_running": "yes"}
+					}
+				}
+			},
+			"contacts": {}
+		}`), WithStrictDecoding())
+		require.Error(t, err)
+		var errs DecodeErrors
+		require.True(t, errors.As(err, &errs), "expected DecodeErrors, got %T", err)
+		paths := []string{}
+		for _, e := range errs {
+			paths = append(paths, e.Path)
+		}
+		assert.Equal(t, []string{
+			"contacts",
+			"resources.book.methods.create.is_long_running",
+			"resources.book.methods.reed",
+			"resources.book.parents",
+		}, paths)
+		assert.Contains(t, err.Error(), `resources.book.methods.reed: unknown key "reed"`)
+		assert.Contains(t, err.Error(), "resources.book.parents: expected an array, got a string")
+	})
+
+	t.Run("yaml errors include positions", func(t *testing.T) {
+		_, err := LoadAPIFromYAML([]byte(`
+name: example.com
+resources:
+  book:
+    singular: book
+    plural: books
+    methods:
+      reed: {}
+`), WithStrictDecoding())
+		assert.ErrorContains(t, err, `line 8, column 13 (resources.book.methods.reed): unknown key "reed"`)
+	})
+
+	t.Run("alias and canonical key together", func(t *testing.T) {
+		_, err := LoadAPIFromJson([]byte(`{
+			"resources": {"book": {"methods": {"get": {}, "read": {}}}}
+		}`), WithStrictDecoding())
+		assert.ErrorContains(t, err, `resources.book.methods.read: "read" is an alias of "get", which is also defined`)
+	})
+}
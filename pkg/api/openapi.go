@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/cases"
@@ -49,7 +50,7 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 		// This will add paths for the simple resource case.
 		if len(*parentPWPS) == 0 {
 			*parentPWPS = append(*parentPWPS, PathWithParams{
-				Pattern: "", Params: []openapi.Parameter{},
+				Pattern: "", Params: []openapi.Parameter{}, Collection: collection,
 			})
 		}
 		patterns := []string{}
@@ -86,10 +87,20 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 			},
 		}
 		for _, pwp := range *parentPWPS {
-			resourcePath := fmt.Sprintf("%s%s/{%s_id}", pwp.Pattern, collection, singularSnake)
+			// clip the params, so that appending to them for one method
+			// never overwrites the params of another.
+			pwp.Params = slices.Clip(pwp.Params)
+			resourcePath := fmt.Sprintf("%s%s/{%s_id}", pwp.Pattern, pwp.Collection, singularSnake)
+			resourceParams := slices.Clip(append(pwp.Params, idParam))
+			if r.Singleton {
+				// a singleton is addressed by the path of its parent,
+				// followed by its name.
+				resourcePath = fmt.Sprintf("%s%s", pwp.Pattern, pwp.Collection)
+				resourceParams = pwp.Params
+			}
 			patterns = append(patterns, resourcePath[1:])
 			if r.Methods.List != nil {
-				listPath := fmt.Sprintf("%s%s", pwp.Pattern, collection)
+				listPath := fmt.Sprintf("%s%s", pwp.Pattern, pwp.Collection)
 				responseProperties := map[string]openapi.Schema{
 					constants.FIELD_RESULTS_NAME: {
 						Type:  "array",
@@ -136,6 +147,30 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					})
 				}
+				if r.SupportsSoftDelete() {
+					params = append(params, openapi.Parameter{
+						In:       "query",
+						Name:     constants.FIELD_SHOW_DELETED_NAME,
+						Required: false,
+						Schema: &openapi.Schema{
+							Type: "boolean",
+						},
+					})
+				}
+				if r.Methods.List.SupportsReadMask {
+					params = append(params, readMaskParam)
+				}
+				if r.Methods.List.SupportsOrderBy {
+					params = append(params, openapi.Parameter{
+						In:                  "query",
+						Name:                constants.FIELD_ORDER_BY_NAME,
+						Required:            false,
+						XAEPOrderableFields: r.Methods.List.OrderableFields,
+						Schema: &openapi.Schema{
+							Type: "string",
+						},
+					})
+				}
 				if r.Methods.List.SupportsFilter {
 					params = append(params, openapi.Parameter{
 						In:       "query",
@@ -167,7 +202,7 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 				addMethodToPath(paths, listPath, "get", methodInfo)
 			}
 			if r.Methods.Create != nil {
-				createPath := fmt.Sprintf("%s%s", pwp.Pattern, collection)
+				createPath := fmt.Sprintf("%s%s", pwp.Pattern, pwp.Collection)
 				params := pwp.Params
 				if r.Methods.Create.SupportsUserSettableCreate {
 					params = append(params, openapi.Parameter{
@@ -179,6 +214,12 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					})
 				}
+				if r.Methods.Create.SupportsValidateOnly {
+					params = append(params, validateOnlyParam)
+				}
+				if r.Methods.Create.SupportsRequestId {
+					params = append(params, requestIdParam)
+				}
 				methodInfo := openapi.Operation{
 					OperationID: fmt.Sprintf("Create%s", cases.SnakeToPascalCase(singularSnake)),
 					Description: fmt.Sprintf("Create method for %s", r.Singular),
@@ -210,10 +251,14 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 				addMethodToPath(paths, createPath, "post", methodInfo)
 			}
 			if r.Methods.Get != nil {
+				params := resourceParams
+				if r.Methods.Get.SupportsReadMask {
+					params = append(params, readMaskParam)
+				}
 				methodInfo := openapi.Operation{
 					OperationID: fmt.Sprintf("Get%s", cases.SnakeToPascalCase(singularSnake)),
 					Description: fmt.Sprintf("Get method for %s", r.Singular),
-					Parameters:  append(pwp.Params, idParam),
+					Parameters:  params,
 					Responses: map[string]openapi.Response{
 						"200": resourceResponse,
 					},
@@ -221,10 +266,17 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 				addMethodToPath(paths, resourcePath, "get", methodInfo)
 			}
 			if r.Methods.Update != nil {
+				params := resourceParams
+				if r.Methods.Update.SupportsValidateOnly {
+					params = append(params, validateOnlyParam)
+				}
+				if r.Methods.Update.SupportsRequestId {
+					params = append(params, requestIdParam)
+				}
 				methodInfo := openapi.Operation{
 					OperationID: fmt.Sprintf("Update%s", cases.SnakeToPascalCase(singularSnake)),
 					Description: fmt.Sprintf("Update method for %s", r.Singular),
-					Parameters:  append(pwp.Params, idParam),
+					Parameters:  params,
 					RequestBody: &openapi.RequestBody{
 						Required: true,
 						Content: map[string]openapi.MediaType{
@@ -267,11 +319,12 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					}
 				}
+				addPreconditions(r, &methodInfo)
 				addMethodToPath(paths, resourcePath, "patch", methodInfo)
 			}
 			if r.Methods.Delete != nil {
 				responseSchema := &openapi.Schema{}
-				params := append(pwp.Params, idParam)
+				params := resourceParams
 				if len(r.Children) > 0 {
 					params = append(params, openapi.Parameter{
 						In:       "query",
@@ -282,6 +335,12 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					})
 				}
+				if r.Methods.Delete.SupportsValidateOnly {
+					params = append(params, validateOnlyParam)
+				}
+				if r.Methods.Delete.SupportsRequestId {
+					params = append(params, requestIdParam)
+				}
 				methodInfo := openapi.Operation{
 					OperationID: fmt.Sprintf("Delete%s", cases.SnakeToPascalCase(singularSnake)),
 					Description: fmt.Sprintf("Delete method for %s", r.Singular),
@@ -297,6 +356,13 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					},
 				}
+				if r.SupportsSoftDelete() {
+					// a soft-deleted resource is returned, rather than nothing.
+					responseSchema = resourceSchema
+					methodInfo.Responses = map[string]openapi.Response{
+						"200": resourceResponse,
+					}
+				}
 				if r.Methods.Delete.IsLongRunning {
 					methodInfo.XAEPLongRunningOperation = &openapi.XAEPLongRunningOperation{
 						Response: openapi.XAEPLongRunningOperationResponse{
@@ -316,19 +382,29 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					}
 				}
+				addPreconditions(r, &methodInfo)
 				addMethodToPath(paths, resourcePath, "delete", methodInfo)
 			}
-			if r.Methods.Apply != nil {
+			if r.Methods.Undelete != nil {
 				methodInfo := openapi.Operation{
-					OperationID: fmt.Sprintf("Apply%s", cases.SnakeToPascalCase(singularSnake)),
-					Description: fmt.Sprintf("Apply method for %s", r.Singular),
-					Parameters:  append(pwp.Params, idParam),
-					RequestBody: &bodyParam,
+					OperationID: fmt.Sprintf("Undelete%s", cases.SnakeToPascalCase(singularSnake)),
+					Description: fmt.Sprintf("Undelete method for %s", r.Singular),
+					Parameters:  resourceParams,
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content: map[string]openapi.MediaType{
+							"application/json": {
+								Schema: &openapi.Schema{
+									Type: "object",
+								},
+							},
+						},
+					},
 					Responses: map[string]openapi.Response{
 						"200": resourceResponse,
 					},
 				}
-				if r.Methods.Apply.IsLongRunning {
+				if r.Methods.Undelete.IsLongRunning {
 					methodInfo.XAEPLongRunningOperation = &openapi.XAEPLongRunningOperation{
 						Response: openapi.XAEPLongRunningOperationResponse{
 							Schema: resourceSchema,
@@ -347,58 +423,29 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					}
 				}
-				addMethodToPath(paths, resourcePath, "put", methodInfo)
+				addMethodToPath(paths, resourcePath+":undelete", "post", methodInfo)
 			}
-			for _, custom := range r.CustomMethods {
-				// Ensure custom.Response and custom.Request are not nil
-				if custom.Response == nil {
-					custom.Response = &openapi.Schema{
-						Type: "object",
-					}
-				}
-				if custom.Request == nil {
-					custom.Request = &openapi.Schema{
-						Type: "object",
-					}
+			if r.Methods.Apply != nil {
+				params := resourceParams
+				if r.Methods.Apply.SupportsValidateOnly {
+					params = append(params, validateOnlyParam)
 				}
-				// Remove XAEPFieldNumber from custom method schemas
-				removeXAEPFieldNumber(custom.Request)
-				removeXAEPFieldNumber(custom.Response)
-				methodType := "get"
-				if custom.Method == "POST" {
-					methodType = "post"
+				if r.Methods.Apply.SupportsRequestId {
+					params = append(params, requestIdParam)
 				}
-				cmPath := fmt.Sprintf("%s:%s", resourcePath, custom.Name)
 				methodInfo := openapi.Operation{
-					OperationID: fmt.Sprintf(":%s%s", cases.SnakeToPascalCase(custom.Name), cases.SnakeToPascalCase(singularSnake)),
-					Description: fmt.Sprintf("Custom method %s for %s", custom.Name, r.Singular),
-					Parameters:  append(pwp.Params, idParam),
+					OperationID: fmt.Sprintf("Apply%s", cases.SnakeToPascalCase(singularSnake)),
+					Description: fmt.Sprintf("Apply method for %s", r.Singular),
+					Parameters:  params,
+					RequestBody: &bodyParam,
 					Responses: map[string]openapi.Response{
-						"200": {
-							Description: "Successful response",
-							Content: map[string]openapi.MediaType{
-								"application/json": {
-									Schema: custom.Response,
-								},
-							},
-						},
+						"200": resourceResponse,
 					},
 				}
-				if custom.Method == "POST" {
-					methodInfo.RequestBody = &openapi.RequestBody{
-						Required: true,
-						Content: map[string]openapi.MediaType{
-							"application/json": {
-								Schema: custom.Request,
-							},
-						},
-					}
-				}
-				// Ensure the response schema for long-running operations is correctly set
-				if custom.IsLongRunning {
+				if r.Methods.Apply.IsLongRunning {
 					methodInfo.XAEPLongRunningOperation = &openapi.XAEPLongRunningOperation{
 						Response: openapi.XAEPLongRunningOperationResponse{
-							Schema: custom.Response,
+							Schema: resourceSchema,
 						},
 					}
 					methodInfo.Responses = map[string]openapi.Response{
@@ -414,8 +461,29 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 						},
 					}
 				}
+				addPreconditions(r, &methodInfo)
+				addMethodToPath(paths, resourcePath, "put", methodInfo)
+			}
+			for _, custom := range r.CustomMethods {
+				cmPath := fmt.Sprintf("%s:%s", resourcePath, custom.Name)
+				methodType, methodInfo := customMethodOperation(custom,
+					fmt.Sprintf(":%s%s", cases.SnakeToPascalCase(custom.Name), cases.SnakeToPascalCase(singularSnake)),
+					fmt.Sprintf("Custom method %s for %s", custom.Name, r.Singular),
+					resourceParams,
+				)
 				addMethodToPath(paths, cmPath, methodType, methodInfo)
 			}
+			for _, custom := range r.CollectionCustomMethods {
+				cmPath := fmt.Sprintf("%s%s:%s", pwp.Pattern, pwp.Collection, custom.Name)
+				methodType, methodInfo := customMethodOperation(custom,
+					fmt.Sprintf(":%s%s", cases.SnakeToPascalCase(custom.Name), cases.SnakeToPascalCase(cases.KebabToSnakeCase(r.Plural))),
+					fmt.Sprintf("Custom method %s for the collection of %s", custom.Name, r.Plural),
+					pwp.Params,
+				)
+				addMethodToPath(paths, cmPath, methodType, methodInfo)
+			}
+			addBatchMethods(paths, r, pwp, resourceSchema)
+			addRevisionMethods(paths, r, resourcePath, resourceParams, resourceSchema)
 		}
 		d.XAEPResource = &openapi.XAEPResource{
 			Singular: r.Singular,
@@ -464,6 +532,10 @@ func ConvertToOpenAPI(api *API) (*openapi.OpenAPI, error) {
 type PathWithParams struct {
 	Pattern string
 	Params  []openapi.Parameter
+	// Collection is the collection of the resource under Pattern,
+	// with a leading slash. It can differ between the patterns
+	// of a resource with multiple parents.
+	Collection string
 }
 
 // generate the x-aep-patterns for the parent resources, along with the patterns
@@ -474,7 +546,7 @@ type PathWithParams struct {
 //
 // There are two algorithms that are used:
 //
-// 1. if PatternElems are present, then those will be used. This helps
+// 1. if Patterns are present, then those will be used. This helps
 // handle the situation where the resource structs were retrieved from a parsed
 // OpenAPI definition, where the plural of the parents aren't necessarily clear,
 // or the pattern element naming may not completely match the resource names.
@@ -482,33 +554,50 @@ type PathWithParams struct {
 // 2. Otherwise, we'll use the parent resources, and generate the collection
 // names. This works for the case where the resource hierarchy is generated from
 // scratch. This Algorithm will result in the fully AEP-compliant collection
-// names.
+// names. A PathWithParams is returned for every pattern of every parent.
+//
+// The returned collection name is the one of the primary pattern; the
+// collection under each pattern is set on the PathWithParams.
 func generateParentPatternsWithParams(r *Resource) (string, *[]PathWithParams) {
-	// case 1: pattern elems are present, so we use them.
-	// TODO(yft): support multiple patterns
-	if len(r.patternElems) > 0 {
-		collection := fmt.Sprintf("/%s", r.patternElems[len(r.patternElems)-2])
-		params := []openapi.Parameter{}
-		for i := 0; i < len(r.patternElems)-2; i += 2 {
-			pElem := r.patternElems[i+1]
-			// Extract the parameter name without the _id suffix
-			paramName := pElem[1 : len(pElem)-1]
-			params = append(params, openapi.Parameter{
-				In:       "path",
-				Name:     paramName,
-				Required: true,
-				Schema: &openapi.Schema{
-					Type: "string",
-				},
-			})
-		}
-		pattern := strings.Join(r.patternElems[0:len(r.patternElems)-2], "/")
-		if pattern != "" {
-			pattern = fmt.Sprintf("/%s", pattern)
+	// case 1: patterns are present, so we use them.
+	if len(r.Patterns) > 0 {
+		// the number of trailing elements identifying the resource
+		// within its parent: the collection and the id, or only the
+		// name of a singleton.
+		suffixLen := 2
+		if r.Singleton {
+			suffixLen = 1
 		}
-		return collection, &[]PathWithParams{
-			{Pattern: pattern, Params: params},
+		allElems := r.AllPatternElems()
+		primary := allElems[0]
+		collection := fmt.Sprintf("/%s", primary[len(primary)-suffixLen])
+		pwps := []PathWithParams{}
+		for _, patternElems := range allElems {
+			params := []openapi.Parameter{}
+			for i := 0; i < len(patternElems)-suffixLen; i += 2 {
+				pElem := patternElems[i+1]
+				// Extract the parameter name without the _id suffix
+				paramName := pElem[1 : len(pElem)-1]
+				params = append(params, openapi.Parameter{
+					In:       "path",
+					Name:     paramName,
+					Required: true,
+					Schema: &openapi.Schema{
+						Type: "string",
+					},
+				})
+			}
+			pattern := strings.Join(patternElems[0:len(patternElems)-suffixLen], "/")
+			if pattern != "" {
+				pattern = fmt.Sprintf("/%s", pattern)
+			}
+			pwps = append(pwps, PathWithParams{
+				Pattern:    pattern,
+				Params:     params,
+				Collection: fmt.Sprintf("/%s", patternElems[len(patternElems)-suffixLen]),
+			})
 		}
+		return collection, &pwps
 	}
 	// case 2: no pattern elems, so we need to generate the collection names
 	collection := fmt.Sprintf("/%s", CollectionName(r))
@@ -517,7 +606,6 @@ func generateParentPatternsWithParams(r *Resource) (string, *[]PathWithParams) {
 		singular := parent.Singular
 		// Convert kebab-case singular to snake_case for path variables
 		singularSnakeParam := cases.KebabToSnakeCase(singular) + "_id"
-		basePattern := fmt.Sprintf("/%s/{%s}", CollectionName(parent), singularSnakeParam)
 		baseParam := openapi.Parameter{
 			In:       "path",
 			Name:     singularSnakeParam,
@@ -529,23 +617,335 @@ func generateParentPatternsWithParams(r *Resource) (string, *[]PathWithParams) {
 				ResourceReference: []string{singular},
 			},
 		}
-		if len(parent.ParentResources()) == 0 {
+		parentCollection, parentPWPS := generateParentPatternsWithParams(parent)
+		if len(*parentPWPS) == 0 {
+			parentPWPS = &[]PathWithParams{
+				{Pattern: "", Params: []openapi.Parameter{}, Collection: parentCollection},
+			}
+		}
+		for _, parentPWP := range *parentPWPS {
+			params := append(slices.Clone(parentPWP.Params), baseParam)
+			pattern := fmt.Sprintf("%s%s/{%s}", parentPWP.Pattern, parentPWP.Collection, singularSnakeParam)
+			if parent.Singleton {
+				params = slices.Clone(parentPWP.Params)
+				pattern = fmt.Sprintf("%s%s", parentPWP.Pattern, parentPWP.Collection)
+			}
 			pwps = append(pwps, PathWithParams{
-				Pattern: basePattern,
-				Params:  []openapi.Parameter{baseParam},
+				Pattern:    pattern,
+				Params:     params,
+				Collection: fmt.Sprintf("/%s", CollectionNameForParent(r, parent)),
 			})
-		} else {
-			_, parentPWPS := generateParentPatternsWithParams(parent)
-			for _, parentPWP := range *parentPWPS {
-				params := append(parentPWP.Params, baseParam)
-				pattern := fmt.Sprintf("%s%s", parentPWP.Pattern, basePattern)
-				pwps = append(pwps, PathWithParams{Pattern: pattern, Params: params})
-			}
 		}
 	}
 	return collection, &pwps
 }
 
+// addBatchMethods adds the batch methods of the resource, which are
+// custom methods on the collection of the resource.
+func addBatchMethods(paths map[string]*openapi.PathItem, r *Resource, pwp PathWithParams, resourceSchema *openapi.Schema) {
+	singularSnake := cases.KebabToSnakeCase(r.Singular)
+	collectionPath := fmt.Sprintf("%s%s", pwp.Pattern, pwp.Collection)
+	pathsSchema := &openapi.Schema{
+		Type: "array",
+		Items: &openapi.Schema{
+			Type: "string",
+		},
+	}
+	resultsSchema := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]openapi.Schema{
+			constants.FIELD_RESULTS_NAME: {
+				Type:  "array",
+				Items: resourceSchema,
+			},
+		},
+	}
+	batchOperation := func(name string, params []openapi.Parameter, request, response *openapi.Schema, isLongRunning bool) openapi.Operation {
+		return methodOperation(fmt.Sprintf("%s%s", name, cases.SnakeToPascalCase(singularSnake)),
+			fmt.Sprintf("%s method for %s", name, r.Singular), params, request, response, isLongRunning)
+	}
+	if r.Methods.BatchGet != nil {
+		params := append(pwp.Params, openapi.Parameter{
+			In:       "query",
+			Name:     constants.FIELD_PATHS_NAME,
+			Required: true,
+			Schema:   pathsSchema,
+		})
+		addMethodToPath(paths, collectionPath+":batchGet", "get",
+			batchOperation("BatchGet", params, nil, resultsSchema, false))
+	}
+	if r.Methods.BatchCreate != nil {
+		requestProperties := map[string]openapi.Schema{
+			constants.FIELD_PARENT_NAME: {Type: "string"},
+			singularSnake:               *resourceSchema,
+		}
+		if r.Methods.Create != nil && r.Methods.Create.SupportsUserSettableCreate {
+			requestProperties[constants.FIELD_ID_NAME] = openapi.Schema{Type: "string"}
+		}
+		request := batchRequestSchema(requestProperties, []string{singularSnake})
+		addMethodToPath(paths, collectionPath+":batchCreate", "post",
+			batchOperation("BatchCreate", pwp.Params, request, resultsSchema, r.Methods.BatchCreate.IsLongRunning))
+	}
+	if r.Methods.BatchUpdate != nil {
+		request := batchRequestSchema(map[string]openapi.Schema{
+			constants.FIELD_PATH_NAME:        {Type: "string"},
+			singularSnake:                    *resourceSchema,
+			constants.FIELD_UPDATE_MASK_NAME: {Type: "string"},
+		}, []string{constants.FIELD_PATH_NAME, singularSnake})
+		addMethodToPath(paths, collectionPath+":batchUpdate", "post",
+			batchOperation("BatchUpdate", pwp.Params, request, resultsSchema, r.Methods.BatchUpdate.IsLongRunning))
+	}
+	if r.Methods.BatchDelete != nil {
+		request := &openapi.Schema{
+			Type: "object",
+			Properties: map[string]openapi.Schema{
+				constants.FIELD_PATHS_NAME: *pathsSchema,
+			},
+			Required: []string{constants.FIELD_PATHS_NAME},
+		}
+		addMethodToPath(paths, collectionPath+":batchDelete", "post",
+			batchOperation("BatchDelete", pwp.Params, request, &openapi.Schema{}, r.Methods.BatchDelete.IsLongRunning))
+	}
+}
+
+// validateOnlyParam is the query parameter of methods that can validate
+// a request without applying it (aep.dev/163).
+var validateOnlyParam = openapi.Parameter{
+	In:       "query",
+	Name:     constants.FIELD_VALIDATE_ONLY_NAME,
+	Required: false,
+	Schema: &openapi.Schema{
+		Type: "boolean",
+	},
+}
+
+// readMaskParam is the query parameter of methods that return only the
+// fields of a resource listed in a field mask (aep.dev/157).
+var readMaskParam = openapi.Parameter{
+	In:       "query",
+	Name:     constants.FIELD_READ_MASK_NAME,
+	Required: false,
+	Schema: &openapi.Schema{
+		Type: "string",
+	},
+}
+
+// requestIdParam is the query parameter of methods that apply a
+// request only once, however often it is retried (aep.dev/155).
+var requestIdParam = openapi.Parameter{
+	In:       "query",
+	Name:     constants.FIELD_REQUEST_ID_NAME,
+	Required: false,
+	Schema: &openapi.Schema{
+		Type: "string",
+	},
+}
+
+// addPreconditions documents the If-Match header of a method that
+// modifies a resource with an etag (aep.dev/154), and the response
+// when the etag does not match.
+func addPreconditions(r *Resource, methodInfo *openapi.Operation) {
+	if !r.SupportsEtag {
+		return
+	}
+	methodInfo.Parameters = append(slices.Clip(methodInfo.Parameters), openapi.Parameter{
+		In:          "header",
+		Name:        constants.HEADER_IF_MATCH,
+		Description: "If set, the request only succeeds if the etag of the resource matches.",
+		Required:    false,
+		Schema: &openapi.Schema{
+			Type: "string",
+		},
+	})
+	methodInfo.Responses["412"] = openapi.Response{
+		Description: "The etag in the If-Match header does not match the etag of the resource.",
+	}
+}
+
+// addRevisionMethods adds the revision methods of the resource
+// (aep.dev/162). A revision is addressed by the path of the resource
+// followed by @{revision_id}.
+func addRevisionMethods(paths map[string]*openapi.PathItem, r *Resource, resourcePath string, resourceParams []openapi.Parameter, resourceSchema *openapi.Schema) {
+	singularPascal := cases.SnakeToPascalCase(cases.KebabToSnakeCase(r.Singular))
+	revisionPath := resourcePath + "@{" + constants.FIELD_REVISION_ID_NAME + "}"
+	revisionParams := slices.Clip(append(resourceParams, openapi.Parameter{
+		In:       "path",
+		Name:     constants.FIELD_REVISION_ID_NAME,
+		Required: true,
+		Schema: &openapi.Schema{
+			Type: "string",
+		},
+	}))
+	revisionOperation := func(operationID string, params []openapi.Parameter, request, response *openapi.Schema, isLongRunning bool) openapi.Operation {
+		return methodOperation(operationID, fmt.Sprintf("%s method for %s", operationID, r.Singular), params, request, response, isLongRunning)
+	}
+	if r.Methods.ListRevisions != nil {
+		params := append(resourceParams,
+			openapi.Parameter{
+				In:       "query",
+				Name:     constants.FIELD_MAX_PAGE_SIZE_NAME,
+				Required: false,
+				Schema: &openapi.Schema{
+					Type: "integer",
+				},
+			},
+			openapi.Parameter{
+				In:       "query",
+				Name:     constants.FIELD_PAGE_TOKEN_NAME,
+				Required: false,
+				Schema: &openapi.Schema{
+					Type: "string",
+				},
+			},
+		)
+		response := &openapi.Schema{
+			Type: "object",
+			Properties: map[string]openapi.Schema{
+				constants.FIELD_RESULTS_NAME: {
+					Type:  "array",
+					Items: resourceSchema,
+				},
+				constants.FIELD_NEXT_PAGE_TOKEN_NAME: {
+					Type: "string",
+				},
+			},
+		}
+		addMethodToPath(paths, resourcePath+"/revisions", "get",
+			revisionOperation(fmt.Sprintf("List%sRevisions", singularPascal), params, nil, response, false))
+	}
+	if r.Methods.GetRevision != nil {
+		addMethodToPath(paths, revisionPath, "get",
+			revisionOperation(fmt.Sprintf("Get%sRevision", singularPascal), revisionParams, nil, resourceSchema, false))
+	}
+	if r.Methods.Commit != nil {
+		request := &openapi.Schema{
+			Type: "object",
+		}
+		addMethodToPath(paths, resourcePath+":commit", "post",
+			revisionOperation(fmt.Sprintf("Commit%s", singularPascal), resourceParams, request, resourceSchema, r.Methods.Commit.IsLongRunning))
+	}
+	if r.Methods.Rollback != nil {
+		request := &openapi.Schema{
+			Type: "object",
+			Properties: map[string]openapi.Schema{
+				constants.FIELD_REVISION_ID_NAME: {Type: "string"},
+			},
+			Required: []string{constants.FIELD_REVISION_ID_NAME},
+		}
+		addMethodToPath(paths, resourcePath+":rollback", "post",
+			revisionOperation(fmt.Sprintf("Rollback%s", singularPascal), resourceParams, request, resourceSchema, r.Methods.Rollback.IsLongRunning))
+	}
+	if r.Methods.TagRevision != nil {
+		request := &openapi.Schema{
+			Type: "object",
+			Properties: map[string]openapi.Schema{
+				constants.FIELD_TAG_NAME: {Type: "string"},
+			},
+			Required: []string{constants.FIELD_TAG_NAME},
+		}
+		addMethodToPath(paths, revisionPath+":tagRevision", "post",
+			revisionOperation(fmt.Sprintf("Tag%sRevision", singularPascal), revisionParams, request, resourceSchema, false))
+	}
+}
+
+// batchRequestSchema returns the schema of a batch request, which
+// holds a list of requests of the corresponding standard method.
+func batchRequestSchema(requestProperties map[string]openapi.Schema, required []string) *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]openapi.Schema{
+			constants.FIELD_REQUESTS_NAME: {
+				Type: "array",
+				Items: &openapi.Schema{
+					Type:       "object",
+					Properties: requestProperties,
+					Required:   required,
+				},
+			},
+		},
+		Required: []string{constants.FIELD_REQUESTS_NAME},
+	}
+}
+
+// customMethodOperation returns the HTTP method and the operation of a
+// custom method.
+func customMethodOperation(custom *CustomMethod, operationID, description string, params []openapi.Parameter) (string, openapi.Operation) {
+	// Ensure custom.Response and custom.Request are not nil
+	if custom.Response == nil {
+		custom.Response = &openapi.Schema{
+			Type: "object",
+		}
+	}
+	if custom.Request == nil {
+		custom.Request = &openapi.Schema{
+			Type: "object",
+		}
+	}
+	// Remove XAEPFieldNumber from custom method schemas
+	removeXAEPFieldNumber(custom.Request)
+	removeXAEPFieldNumber(custom.Response)
+	methodType := "get"
+	var request *openapi.Schema
+	if custom.Method == "POST" {
+		methodType = "post"
+		request = custom.Request
+	}
+	methodInfo := methodOperation(operationID, description, params, request, custom.Response, custom.IsLongRunning)
+	return methodType, methodInfo
+}
+
+// methodOperation returns the operation of a method with the given
+// request (nil for methods without a body) and response schemas.
+// Long-running methods respond with an operation, whose response is the
+// response schema.
+func methodOperation(operationID, description string, params []openapi.Parameter, request, response *openapi.Schema, isLongRunning bool) openapi.Operation {
+	methodInfo := openapi.Operation{
+		OperationID: operationID,
+		Description: description,
+		Parameters:  params,
+		Responses: map[string]openapi.Response{
+			"200": {
+				Description: "Successful response",
+				Content: map[string]openapi.MediaType{
+					"application/json": {
+						Schema: response,
+					},
+				},
+			},
+		},
+	}
+	if request != nil {
+		methodInfo.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				"application/json": {
+					Schema: request,
+				},
+			},
+		}
+	}
+	if isLongRunning {
+		methodInfo.XAEPLongRunningOperation = &openapi.XAEPLongRunningOperation{
+			Response: openapi.XAEPLongRunningOperationResponse{
+				Schema: response,
+			},
+		}
+		methodInfo.Responses = map[string]openapi.Response{
+			"200": {
+				Description: "Long-running operation response",
+				Content: map[string]openapi.MediaType{
+					"application/json": {
+						Schema: &openapi.Schema{
+							Ref: AEP_OPERATION_REF,
+						},
+					},
+				},
+			},
+		}
+	}
+	return methodInfo
+}
+
 func addMethodToPath(paths map[string]*openapi.PathItem, path, method string, methodInfo openapi.Operation) {
 	methods, ok := paths[path]
 	if !ok {
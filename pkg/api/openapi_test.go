@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/aep-dev/aep-lib-go/pkg/constants"
@@ -296,6 +297,59 @@ func TestToOpenAPI(t *testing.T) {
 	}
 }
 
+func TestMultiplePatternsRoundTrip(t *testing.T) {
+	book := &Resource{
+		Singular: "book",
+		Plural:   "books",
+		Patterns: []string{
+			"publishers/{publisher_id}/books/{book_id}",
+			"authors/{author_id}/books/{book_id}",
+		},
+		Schema: &openapi.Schema{Type: "object"},
+		Methods: Methods{
+			Get:  &GetMethod{},
+			List: &ListMethod{},
+		},
+	}
+	a := &API{
+		Name:      "example.com",
+		ServerURL: "https://api.example.com",
+		Resources: map[string]*Resource{"book": book},
+	}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	for _, path := range []string{
+		"/publishers/{publisher_id}/books",
+		"/publishers/{publisher_id}/books/{book_id}",
+		"/authors/{author_id}/books",
+		"/authors/{author_id}/books/{book_id}",
+	} {
+		assert.Contains(t, openAPI.Paths, path)
+	}
+	assert.Equal(t, book.Patterns, openAPI.Components.Schemas["book"].XAEPResource.Patterns)
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, book.Patterns, parsed.Resources["book"].GetPatterns())
+}
+
+func TestInvalidPatterns(t *testing.T) {
+	a := &API{
+		Resources: map[string]*Resource{
+			"book": {
+				Singular: "book",
+				Plural:   "books",
+				Patterns: []string{"publishers/{publisher_id}/books"},
+				Schema:   &openapi.Schema{},
+			},
+		},
+	}
+	err := AddImplicitFieldsAndValidate(a)
+	assert.ErrorContains(t, err, `pattern "publishers/{publisher_id}/books" of resource book is not a valid resource pattern`)
+}
+
 func TestGenerateParentPatternsWithParams(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -306,8 +360,8 @@ func TestGenerateParentPatternsWithParams(t *testing.T) {
 		{
 			name: "with pattern elements",
 			resource: &Resource{
-				patternElems: []string{"databases", "{database_id}", "tables", "{table_id}"},
-				Singular:     "table",
+				Patterns: []string{"databases/{database_id}/tables/{table_id}"},
+				Singular: "table",
 			},
 			wantCollection: "/tables",
 			wantPathParams: &[]PathWithParams{
@@ -332,8 +386,8 @@ func TestGenerateParentPatternsWithParams(t *testing.T) {
 		{
 			name: "with pattern elements no nesting",
 			resource: &Resource{
-				patternElems: []string{"databases", "{database_id}"},
-				Singular:     "database",
+				Patterns: []string{"databases/{database_id}"},
+				Singular: "database",
 			},
 			wantCollection: "/databases",
 			wantPathParams: &[]PathWithParams{
@@ -344,6 +398,45 @@ func TestGenerateParentPatternsWithParams(t *testing.T) {
 			},
 		},
 
+		{
+			name: "with multiple patterns",
+			resource: &Resource{
+				Patterns: []string{
+					"databases/{database_id}/tables/{table_id}",
+					"accounts/{account_id}/tables/{table_id}",
+				},
+				Singular: "table",
+			},
+			wantCollection: "/tables",
+			wantPathParams: &[]PathWithParams{
+				{
+					Pattern: "/databases/{database_id}",
+					Params: []openapi.Parameter{
+						{
+							In:       "path",
+							Name:     "database_id",
+							Required: true,
+							Schema: &openapi.Schema{
+								Type: "string",
+							},
+						},
+					},
+				},
+				{
+					Pattern: "/accounts/{account_id}",
+					Params: []openapi.Parameter{
+						{
+							In:       "path",
+							Name:     "account_id",
+							Required: true,
+							Schema: &openapi.Schema{
+								Type: "string",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "without pattern elements",
 			resource: &Resource{
@@ -661,6 +754,40 @@ func TestDereferenceSchemaWithExternalReference(t *testing.T) {
 	assert.Contains(t, resolvedSchema.Properties, "age", "Expected 'age' property in schema")
 }
 
+func TestParseOpenAPIYAML(t *testing.T) {
+	doc := `
+openapi: 3.1.0
+info:
+  title: example
+  version: v1
+servers:
+  - url: https://api.example.com
+paths:
+  /widgets/{widget_id}:
+    get:
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/widget"
+components:
+  schemas:
+    widget:
+      type: object
+`
+	openAPI, err := openapi.ParseOpenAPI([]byte(doc))
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1.0", openAPI.OpenAPI)
+	assert.Equal(t, "https://api.example.com", openAPI.Servers[0].URL)
+	assert.Equal(t, "#/components/schemas/widget",
+		openAPI.Paths["/widgets/{widget_id}"].Get.Responses["200"].Content["application/json"].Schema.Ref)
+
+	_, err = openapi.ParseOpenAPI([]byte("openapi: 3.1.0\npaths: []\n"))
+	assert.ErrorContains(t, err, "line 2, column 8 (paths)")
+}
+
 func TestXAEPFieldNumberIsZeroed(t *testing.T) {
 	// Create a resource with field_number set in XAEPField
 	resource := &Resource{
@@ -798,3 +925,591 @@ func TestXAEPFieldNumberIsZeroed(t *testing.T) {
 	// we verify that the removeXAEPFieldNumber function is called on all schemas during conversion
 	// The function should recursively zero all field_number values in XAEPField structures
 }
+
+// multiParentAPI returns an API where books can be reached both from
+// publishers and authors.
+func multiParentAPI() *API {
+	a := &API{
+		Name:      "example.com",
+		ServerURL: "https://api.example.com",
+		Resources: map[string]*Resource{
+			"publisher": {
+				Singular: "publisher",
+				Plural:   "publishers",
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods:  Methods{Get: &GetMethod{}},
+			},
+			"author": {
+				Singular: "author",
+				Plural:   "authors",
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods:  Methods{Get: &GetMethod{}},
+			},
+			"book": {
+				Singular: "book",
+				Plural:   "books",
+				Parents:  []string{"publisher", "author"},
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods: Methods{
+					Get:    &GetMethod{},
+					List:   &ListMethod{},
+					Create: &CreateMethod{},
+				},
+			},
+			"publisher-note": {
+				Singular: "publisher-note",
+				Plural:   "publisher-notes",
+				Parents:  []string{"publisher", "author"},
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods:  Methods{Get: &GetMethod{}},
+			},
+			"book-edition": {
+				Singular: "book-edition",
+				Plural:   "book-editions",
+				Parents:  []string{"book"},
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods:  Methods{Get: &GetMethod{}},
+			},
+		},
+	}
+	if err := AddImplicitFieldsAndValidate(a); err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func TestMultipleParents(t *testing.T) {
+	a := multiParentAPI()
+
+	assert.Equal(t, []string{
+		"publishers/{publisher_id}/books/{book_id}",
+		"authors/{author_id}/books/{book_id}",
+	}, a.Resources["book"].GetPatterns())
+	assert.Equal(t, []string{
+		"publishers/{publisher_id}/books/{book_id}/editions/{book_edition_id}",
+		"authors/{author_id}/books/{book_id}/editions/{book_edition_id}",
+	}, a.Resources["book-edition"].GetPatterns())
+	// the collection name is only deduplicated against the matching parent.
+	assert.Equal(t, []string{
+		"publishers/{publisher_id}/notes/{publisher_note_id}",
+		"authors/{author_id}/publisher-notes/{publisher_note_id}",
+	}, a.Resources["publisher-note"].GetPatterns())
+	assert.Equal(t, "notes", CollectionName(a.Resources["publisher-note"]))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	for _, path := range []string{
+		"/publishers/{publisher_id}/books",
+		"/publishers/{publisher_id}/books/{book_id}",
+		"/authors/{author_id}/books",
+		"/authors/{author_id}/books/{book_id}",
+		"/publishers/{publisher_id}/books/{book_id}/editions/{book_edition_id}",
+		"/authors/{author_id}/books/{book_id}/editions/{book_edition_id}",
+		"/publishers/{publisher_id}/notes/{publisher_note_id}",
+		"/authors/{author_id}/publisher-notes/{publisher_note_id}",
+	} {
+		assert.Contains(t, openAPI.Paths, path)
+	}
+	for _, r := range a.Resources {
+		assert.ElementsMatch(t, r.GetPatterns(), openAPI.Components.Schemas[r.Singular].XAEPResource.Patterns,
+			"x-aep-resource patterns for %s should match the resource patterns", r.Singular)
+	}
+}
+
+func TestApplyRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBySingular := map[string]*Resource{}
+	for _, r := range parsed.Resources {
+		parsedBySingular[r.Singular] = r
+	}
+	for _, r := range a.Resources {
+		parsedResource, ok := parsedBySingular[r.Singular]
+		if !assert.True(t, ok, "resource %s should exist", r.Singular) {
+			continue
+		}
+		assert.Equal(t, r.Methods.Apply, parsedResource.Methods.Apply, "apply method of %s", r.Singular)
+	}
+}
+
+func TestCollectionCustomMethodsRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.CollectionCustomMethods = []*CustomMethod{
+		{
+			Name:   "search",
+			Method: "POST",
+			Request: &openapi.Schema{
+				Type: "object",
+				Properties: map[string]openapi.Schema{
+					"query": {Type: "string"},
+				},
+			},
+			Response: &openapi.Schema{Type: "object"},
+		},
+	}
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+
+	searchPath, ok := openAPI.Paths["/publishers/{publisher_id}/books:search"]
+	if assert.True(t, ok, "collection custom method path should exist") {
+		assert.NotNil(t, searchPath.Post)
+		assert.Equal(t, ":SearchBooks", searchPath.Post.OperationID)
+		assert.Len(t, searchPath.Post.Parameters, 1)
+	}
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	if assert.Len(t, parsedBook.CollectionCustomMethods, 1) {
+		assert.Equal(t, "search", parsedBook.CollectionCustomMethods[0].Name)
+		assert.Equal(t, "POST", parsedBook.CollectionCustomMethods[0].Method)
+	}
+	// collection custom methods must not be mistaken for create methods
+	// or resource custom methods.
+	for _, cm := range parsedBook.CustomMethods {
+		assert.NotEqual(t, "search", cm.Name)
+	}
+}
+
+func singletonAPI() *API {
+	return &API{
+		Name:      "example.com",
+		ServerURL: "https://api.example.com",
+		Resources: map[string]*Resource{
+			"publisher": {
+				Singular: "publisher",
+				Plural:   "publishers",
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods:  Methods{Get: &GetMethod{}},
+			},
+			"publisher-config": {
+				Singular:  "publisher-config",
+				Plural:    "publisher-configs",
+				Parents:   []string{"publisher"},
+				Singleton: true,
+				Schema: &openapi.Schema{
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"tags": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+					},
+				},
+				Methods: Methods{
+					Get:    &GetMethod{},
+					Update: &UpdateMethod{},
+				},
+				CustomMethods: []*CustomMethod{
+					{Name: "reset", Method: "POST"},
+				},
+			},
+		},
+	}
+}
+
+func TestSingletonResources(t *testing.T) {
+	a := singletonAPI()
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+	config := a.Resources["publisher-config"]
+	assert.Equal(t, []string{"publishers/{publisher_id}/config"}, config.GetPatterns())
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	configPath, ok := openAPI.Paths["/publishers/{publisher_id}/config"]
+	if assert.True(t, ok, "singleton path should exist") {
+		assert.NotNil(t, configPath.Get)
+		assert.NotNil(t, configPath.Patch)
+		assert.Nil(t, configPath.Post)
+		assert.Len(t, configPath.Get.Parameters, 1)
+	}
+	assert.Contains(t, openAPI.Paths, "/publishers/{publisher_id}/config:reset")
+	assert.NotContains(t, openAPI.Paths, "/publishers/{publisher_id}/config/{publisher_config_id}")
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	// resources parsed from OpenAPI are keyed by their snake_case schema name.
+	parsedConfig := parsed.Resources["publisher_config"]
+	if assert.NotNil(t, parsedConfig) {
+		assert.True(t, parsedConfig.Singleton)
+		assert.Equal(t, config.GetPatterns(), parsedConfig.GetPatterns())
+		assert.NotNil(t, parsedConfig.Methods.Get)
+		assert.NotNil(t, parsedConfig.Methods.Update)
+		assert.Nil(t, parsedConfig.Methods.List)
+		assert.Nil(t, parsedConfig.Methods.Create)
+		if assert.Len(t, parsedConfig.CustomMethods, 1) {
+			assert.Equal(t, "reset", parsedConfig.CustomMethods[0].Name)
+		}
+	}
+}
+
+func TestInvalidSingletons(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(r *Resource)
+		expectedError string
+	}{
+		{
+			name:          "without a parent",
+			modify:        func(r *Resource) { r.Parents = nil },
+			expectedError: "singleton resource publisher-config must have a parent",
+		},
+		{
+			name:          "with a list method",
+			modify:        func(r *Resource) { r.Methods.List = &ListMethod{} },
+			expectedError: "singleton resource publisher-config does not support the list method",
+		},
+		{
+			name:          "with a pattern ending with an id",
+			modify:        func(r *Resource) { r.Patterns = []string{"publishers/{publisher_id}/configs/{config_id}"} },
+			expectedError: `pattern "publishers/{publisher_id}/configs/{config_id}" of singleton resource publisher-config must not end with an id`,
+		},
+		{
+			name:          "with a top-level pattern",
+			modify:        func(r *Resource) { r.Patterns = []string{"config"} },
+			expectedError: `pattern "config" of resource publisher-config is not a valid resource pattern`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := singletonAPI()
+			tt.modify(a.Resources["publisher-config"])
+			err := AddImplicitFieldsAndValidate(a)
+			if assert.Error(t, err) {
+				assert.Equal(t, tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestBatchMethodsRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.BatchGet = &BatchGetMethod{}
+	book.Methods.BatchCreate = &BatchCreateMethod{}
+	book.Methods.BatchUpdate = &BatchUpdateMethod{IsLongRunning: true}
+	book.Methods.BatchDelete = &BatchDeleteMethod{}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	batchGet, ok := openAPI.Paths["/publishers/{publisher_id}/books:batchGet"]
+	if assert.True(t, ok, "batchGet path should exist") && assert.NotNil(t, batchGet.Get) {
+		assert.Equal(t, "BatchGetBook", batchGet.Get.OperationID)
+		assert.Equal(t, "paths", batchGet.Get.Parameters[len(batchGet.Get.Parameters)-1].Name)
+	}
+	for _, name := range []string{"batchCreate", "batchUpdate", "batchDelete"} {
+		path, ok := openAPI.Paths["/publishers/{publisher_id}/books:"+name]
+		if assert.True(t, ok, "%s path should exist", name) {
+			assert.NotNil(t, path.Post)
+			assert.NotNil(t, path.Post.RequestBody)
+		}
+	}
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	assert.Equal(t, book.Methods.BatchGet, parsedBook.Methods.BatchGet)
+	assert.Equal(t, book.Methods.BatchCreate, parsedBook.Methods.BatchCreate)
+	assert.Equal(t, book.Methods.BatchUpdate, parsedBook.Methods.BatchUpdate)
+	assert.Equal(t, book.Methods.BatchDelete, parsedBook.Methods.BatchDelete)
+	assert.Empty(t, parsedBook.CollectionCustomMethods, "batch methods should not be parsed as custom methods")
+}
+
+func TestBatchMethodsRequireStandardMethods(t *testing.T) {
+	a := ExampleAPI()
+	a.Resources["publisher"].Methods.BatchUpdate = &BatchUpdateMethod{}
+	err := AddImplicitFieldsAndValidate(a)
+	if assert.Error(t, err) {
+		assert.Equal(t, "resource publisher has a batch_update method, but no update method", err.Error())
+	}
+}
+
+func TestSoftDeleteRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Delete.SupportsSoftDelete = true
+	book.Methods.Undelete = &UndeleteMethod{}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+	assert.Contains(t, book.Schema.Properties, "delete_time")
+	assert.Contains(t, book.Schema.Properties, "expire_time")
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	resourcePath := openAPI.Paths["/publishers/{publisher_id}/books/{book_id}"]
+	if assert.NotNil(t, resourcePath.Delete) {
+		assert.Contains(t, resourcePath.Delete.Responses, "200", "soft delete should return the resource")
+	}
+	undelete, ok := openAPI.Paths["/publishers/{publisher_id}/books/{book_id}:undelete"]
+	if assert.True(t, ok, "undelete path should exist") && assert.NotNil(t, undelete.Post) {
+		assert.Equal(t, "UndeleteBook", undelete.Post.OperationID)
+	}
+	list := openAPI.Paths["/publishers/{publisher_id}/books"]
+	if assert.NotNil(t, list.Get) {
+		assert.True(t, slices.ContainsFunc(list.Get.Parameters, func(p openapi.Parameter) bool {
+			return p.Name == "show_deleted"
+		}), "list should have a show_deleted parameter")
+	}
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	assert.Equal(t, book.Methods.Delete, parsedBook.Methods.Delete)
+	assert.Equal(t, book.Methods.Undelete, parsedBook.Methods.Undelete)
+	assert.False(t, slices.ContainsFunc(parsedBook.CustomMethods, func(cm *CustomMethod) bool {
+		return cm.Name == "undelete"
+	}), "undelete should not be parsed as a custom method")
+	assert.False(t, parsed.Resources["publisher"].SupportsSoftDelete())
+}
+
+func TestUndeleteRequiresSoftDelete(t *testing.T) {
+	a := ExampleAPI()
+	a.Resources["book"].Methods.Undelete = &UndeleteMethod{}
+	err := AddImplicitFieldsAndValidate(a)
+	if assert.Error(t, err) {
+		assert.Equal(t, "resource book has an undelete method, but does not support soft delete", err.Error())
+	}
+}
+
+func TestRevisionsRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.ListRevisions = &ListRevisionsMethod{}
+	book.Methods.GetRevision = &GetRevisionMethod{}
+	book.Methods.Commit = &CommitMethod{}
+	book.Methods.Rollback = &RollbackMethod{IsLongRunning: true}
+	book.Methods.TagRevision = &TagRevisionMethod{}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+	assert.Contains(t, book.Schema.Properties, "revision_id")
+	assert.Contains(t, book.Schema.Properties, "revision_create_time")
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	for path, operationID := range map[string]string{
+		"/publishers/{publisher_id}/books/{book_id}/revisions":                 "ListBookRevisions",
+		"/publishers/{publisher_id}/books/{book_id}@{revision_id}":             "GetBookRevision",
+		"/publishers/{publisher_id}/books/{book_id}:commit":                    "CommitBook",
+		"/publishers/{publisher_id}/books/{book_id}:rollback":                  "RollbackBook",
+		"/publishers/{publisher_id}/books/{book_id}@{revision_id}:tagRevision": "TagBookRevision",
+	} {
+		pathItem, ok := openAPI.Paths[path]
+		if !assert.True(t, ok, "path %s should exist", path) {
+			continue
+		}
+		operation := pathItem.Get
+		if operation == nil {
+			operation = pathItem.Post
+		}
+		if assert.NotNil(t, operation, path) {
+			assert.Equal(t, operationID, operation.OperationID)
+		}
+	}
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	assert.Equal(t, book.Methods.ListRevisions, parsedBook.Methods.ListRevisions)
+	assert.Equal(t, book.Methods.GetRevision, parsedBook.Methods.GetRevision)
+	assert.Equal(t, book.Methods.Commit, parsedBook.Methods.Commit)
+	assert.Equal(t, book.Methods.Rollback, parsedBook.Methods.Rollback)
+	assert.Equal(t, book.Methods.TagRevision, parsedBook.Methods.TagRevision)
+	assert.Equal(t, []string{"publishers/{publisher_id}/books/{book_id}"}, parsedBook.Patterns,
+		"revision paths should not add patterns to the resource")
+	assert.NotContains(t, parsed.Resources, "revision")
+	assert.False(t, slices.ContainsFunc(parsedBook.CustomMethods, func(cm *CustomMethod) bool {
+		return cm.Name == "commit" || cm.Name == "rollback"
+	}), "revision methods should not be parsed as custom methods")
+}
+
+func TestRevisionPathsRequireResource(t *testing.T) {
+	a := ExampleAPI()
+	a.Resources["book"].Methods.GetRevision = &GetRevisionMethod{}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	getRevision := openAPI.Paths["/publishers/{publisher_id}/books/{book_id}@{revision_id}"]
+	// a collection, and a resource that is not defined.
+	openAPI.Paths["/publishers/{publisher_id}/books@{revision_id}"] = getRevision
+	openAPI.Paths["/authors/{author_id}@{revision_id}"] = getRevision
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	assert.NotNil(t, parsed.Resources["book"].Methods.GetRevision)
+	assert.Nil(t, parsed.Resources["publisher"].Methods.GetRevision)
+	assert.Len(t, parsed.Resources, len(a.Resources))
+}
+
+func TestRevisionsRequireGet(t *testing.T) {
+	a := ExampleAPI()
+	a.Resources["publisher"].Methods.Get = nil
+	a.Resources["publisher"].Methods.Commit = &CommitMethod{}
+	err := AddImplicitFieldsAndValidate(a)
+	if assert.Error(t, err) {
+		assert.Equal(t, "resource publisher has revision methods, but no get method", err.Error())
+	}
+}
+
+func TestEtagRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.SupportsEtag = true
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+	assert.Contains(t, book.Schema.Properties, "etag")
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	resourcePath := openAPI.Paths["/publishers/{publisher_id}/books/{book_id}"]
+	for method, op := range map[string]*openapi.Operation{
+		"patch":  resourcePath.Patch,
+		"delete": resourcePath.Delete,
+	} {
+		if !assert.NotNil(t, op, method) {
+			continue
+		}
+		assert.True(t, slices.ContainsFunc(op.Parameters, func(p openapi.Parameter) bool {
+			return p.In == "header" && p.Name == "If-Match"
+		}), "%s should accept an If-Match header", method)
+		assert.Contains(t, op.Responses, "412")
+	}
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	assert.True(t, parsed.Resources["book"].SupportsEtag)
+	assert.False(t, parsed.Resources["publisher"].SupportsEtag)
+}
+
+func TestValidateOnlyRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Create.SupportsValidateOnly = true
+	book.Methods.Update.SupportsValidateOnly = true
+	book.Methods.Delete.SupportsValidateOnly = true
+	book.Methods.Apply = &ApplyMethod{SupportsValidateOnly: true}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	hasValidateOnly := func(op *openapi.Operation) bool {
+		return slices.ContainsFunc(op.Parameters, func(p openapi.Parameter) bool {
+			return p.In == "query" && p.Name == "validate_only"
+		})
+	}
+	resourcePath := openAPI.Paths["/publishers/{publisher_id}/books/{book_id}"]
+	assert.True(t, hasValidateOnly(openAPI.Paths["/publishers/{publisher_id}/books"].Post))
+	assert.True(t, hasValidateOnly(resourcePath.Patch))
+	assert.True(t, hasValidateOnly(resourcePath.Delete))
+	assert.True(t, hasValidateOnly(resourcePath.Put))
+	assert.False(t, hasValidateOnly(resourcePath.Get))
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	assert.Equal(t, book.Methods.Create, parsedBook.Methods.Create)
+	assert.Equal(t, book.Methods.Update, parsedBook.Methods.Update)
+	assert.Equal(t, book.Methods.Delete, parsedBook.Methods.Delete)
+	assert.Equal(t, book.Methods.Apply, parsedBook.Methods.Apply)
+	assert.False(t, parsed.Resources["publisher"].Methods.Create.SupportsValidateOnly)
+}
+
+func TestRequestIdRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Create.SupportsRequestId = true
+	book.Methods.Delete.SupportsRequestId = true
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	create := openAPI.Paths["/publishers/{publisher_id}/books"].Post
+	assert.True(t, slices.ContainsFunc(create.Parameters, func(p openapi.Parameter) bool {
+		return p.In == "query" && p.Name == "request_id"
+	}))
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	assert.Equal(t, book.Methods.Create, parsedBook.Methods.Create)
+	assert.Equal(t, book.Methods.Update, parsedBook.Methods.Update)
+	assert.Equal(t, book.Methods.Delete, parsedBook.Methods.Delete)
+}
+
+func TestReadMaskRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Get.SupportsReadMask = true
+	book.Methods.List.SupportsReadMask = true
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	hasReadMask := func(op *openapi.Operation) bool {
+		return slices.ContainsFunc(op.Parameters, func(p openapi.Parameter) bool {
+			return p.In == "query" && p.Name == "read_mask"
+		})
+	}
+	assert.True(t, hasReadMask(openAPI.Paths["/publishers/{publisher_id}/books/{book_id}"].Get))
+	assert.True(t, hasReadMask(openAPI.Paths["/publishers/{publisher_id}/books"].Get))
+	assert.False(t, hasReadMask(openAPI.Paths["/publishers/{publisher_id}"].Get))
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedBook := parsed.Resources["book"]
+	assert.Equal(t, book.Methods.Get, parsedBook.Methods.Get)
+	assert.True(t, parsedBook.Methods.List.SupportsReadMask)
+	assert.False(t, parsed.Resources["publisher"].Methods.Get.SupportsReadMask)
+}
+
+func TestOrderByRoundTrip(t *testing.T) {
+	a := ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.List.SupportsOrderBy = true
+	book.Methods.List.OrderableFields = []string{"name", "id"}
+	assert.NoError(t, AddImplicitFieldsAndValidate(a))
+
+	openAPI, err := ConvertToOpenAPI(a)
+	assert.NoError(t, err)
+	list := openAPI.Paths["/publishers/{publisher_id}/books"].Get
+	i := slices.IndexFunc(list.Parameters, func(p openapi.Parameter) bool {
+		return p.Name == "order_by"
+	})
+	if assert.NotEqual(t, -1, i, "list should have an order_by parameter") {
+		assert.Equal(t, []string{"name", "id"}, list.Parameters[i].XAEPOrderableFields)
+	}
+
+	parsed, err := GetAPI(openAPI, "", "")
+	assert.NoError(t, err)
+	parsedList := parsed.Resources["book"].Methods.List
+	assert.True(t, parsedList.SupportsOrderBy)
+	assert.Equal(t, book.Methods.List.OrderableFields, parsedList.OrderableFields)
+	assert.False(t, parsed.Resources["publisher"].Methods.List.SupportsOrderBy)
+}
+
+func TestInvalidOrderableFields(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		list     *ListMethod
+		expected string
+	}{
+		{
+			name:     "order_by is not supported",
+			list:     &ListMethod{OrderableFields: []string{"name"}},
+			expected: "resource book has orderable fields, but its list method does not support order_by",
+		},
+		{
+			name:     "unknown field",
+			list:     &ListMethod{SupportsOrderBy: true, OrderableFields: []string{"name", "author.name"}},
+			expected: `orderable field "author.name" of resource book is not a field of the resource`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := ExampleAPI()
+			a.Resources["book"].Methods.List = tc.list
+			err := AddImplicitFieldsAndValidate(a)
+			if assert.Error(t, err) {
+				assert.Equal(t, tc.expected, err.Error())
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/cases"
@@ -22,13 +24,35 @@ type Resource struct {
 	Parents         []string    `json:"parents,omitempty"`
 	parentResources []*Resource `json:"-"`
 	// Children is populated on load
-	Children     []*Resource `json:"-"`
-	patternElems []string    `json:"-"` // TOO(yft): support multiple patterns
+	Children []*Resource `json:"-"`
+	// Patterns are the resource patterns, without a leading slash
+	// (e.g. publishers/{publisher_id}/books/{book_id}). A resource may
+	// be reachable from more than one hierarchy, in which case it has
+	// multiple patterns. The first pattern is the primary one.
+	//
+	// If unset, a pattern is derived from the parent resources.
+	Patterns []string `json:"patterns,omitempty"`
+	// Singleton marks a resource of which there is exactly one instance
+	// per parent (aep.dev/156), e.g. publishers/{publisher_id}/config.
+	// The pattern of a singleton ends with its singular name rather than
+	// a collection and an id, and it only supports the Get and Update
+	// standard methods.
+	Singleton bool `json:"singleton,omitempty"`
+	// SupportsEtag marks a resource that carries an etag (aep.dev/154),
+	// which clients send in an If-Match header to make updates and
+	// deletes conditional on the resource being unchanged.
+	SupportsEtag bool `json:"supports_etag,omitempty"`
+	// patternElems caches the patterns derived from the parents.
+	patternElems [][]string `json:"-"`
 	// the API reference is used to retrieve things like the parent resources.
 	API           *API            `json:"-"`
 	Schema        *openapi.Schema `json:"schema,omitempty"`
 	Methods       Methods         `json:"methods,omitempty"`
 	CustomMethods []*CustomMethod `json:"custom_methods,omitempty"`
+	// CollectionCustomMethods operate on the collection of the resource
+	// rather than on an individual resource
+	// (e.g. POST publishers/{publisher_id}/books:search).
+	CollectionCustomMethods []*CustomMethod `json:"collection_custom_methods,omitempty"`
 }
 
 type Methods struct {
@@ -38,32 +62,152 @@ type Methods struct {
 	Create *CreateMethod `json:"create,omitempty"`
 	Update *UpdateMethod `json:"update,omitempty"`
 	Delete *DeleteMethod `json:"delete,omitempty"`
+	// Undelete restores a soft-deleted resource (aep.dev/164). It
+	// requires the Delete method to support soft delete.
+	Undelete *UndeleteMethod `json:"undelete,omitempty"`
+	// revision methods manage the revisions of a resource
+	// (aep.dev/162). A revision is an immutable snapshot of the
+	// resource, addressed by the path of the resource followed by
+	// @{revision_id}.
+	ListRevisions *ListRevisionsMethod `json:"list_revisions,omitempty"`
+	GetRevision   *GetRevisionMethod   `json:"get_revision,omitempty"`
+	Commit        *CommitMethod        `json:"commit,omitempty"`
+	Rollback      *RollbackMethod      `json:"rollback,omitempty"`
+	TagRevision   *TagRevisionMethod   `json:"tag_revision,omitempty"`
+	// batch methods operate on multiple resources of a collection at
+	// once (aep.dev/231, aep.dev/233, aep.dev/234 and aep.dev/235).
+	BatchGet    *BatchGetMethod    `json:"batch_get,omitempty"`
+	BatchCreate *BatchCreateMethod `json:"batch_create,omitempty"`
+	BatchUpdate *BatchUpdateMethod `json:"batch_update,omitempty"`
+	BatchDelete *BatchDeleteMethod `json:"batch_delete,omitempty"`
+}
+
+// methodAliases maps alternate method names accepted in resource
+// definitions to the key used by Methods: "read" is accepted for "get".
+var methodAliases = map[string]string{
+	"read": "get",
+}
+
+func (m *Methods) UnmarshalJSON(data []byte) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for alias, name := range methodAliases {
+		value, ok := raw[alias]
+		if !ok {
+			continue
+		}
+		if _, ok := raw[name]; ok {
+			return fmt.Errorf("method %q is defined twice, as %q and %q", name, name, alias)
+		}
+		raw[name] = value
+		delete(raw, alias)
+	}
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	// use a type without the UnmarshalJSON method to avoid recursion.
+	type methods Methods
+	return json.Unmarshal(normalized, (*methods)(m))
 }
 
 type CreateMethod struct {
 	SupportsUserSettableCreate bool `json:"supports_user_settable_create"`
 	IsLongRunning              bool `json:"is_long_running"`
+	// SupportsValidateOnly marks methods that accept a validate_only
+	// flag, which validates the request without applying it
+	// (aep.dev/163).
+	SupportsValidateOnly bool `json:"supports_validate_only,omitempty"`
+	// SupportsRequestId marks methods that accept a request_id, with
+	// which a retried request is only applied once (aep.dev/155).
+	SupportsRequestId bool `json:"supports_request_id,omitempty"`
 }
 
 type ApplyMethod struct {
-	IsLongRunning bool `json:"is_long_running"`
+	IsLongRunning        bool `json:"is_long_running"`
+	SupportsValidateOnly bool `json:"supports_validate_only,omitempty"`
+	SupportsRequestId    bool `json:"supports_request_id,omitempty"`
 }
 
 type GetMethod struct {
+	// SupportsReadMask marks methods that accept a read_mask, which
+	// limits the fields of the returned resources (aep.dev/157).
+	SupportsReadMask bool `json:"supports_read_mask,omitempty"`
 }
 
 type UpdateMethod struct {
-	IsLongRunning bool `json:"is_long_running"`
+	IsLongRunning        bool `json:"is_long_running"`
+	SupportsValidateOnly bool `json:"supports_validate_only,omitempty"`
+	SupportsRequestId    bool `json:"supports_request_id,omitempty"`
 }
 
 type ListMethod struct {
 	HasUnreachableResources bool `json:"has_unreachable_resources"`
 	SupportsFilter          bool `json:"supports_filter"`
 	SupportsSkip            bool `json:"supports_skip"`
+	SupportsReadMask        bool `json:"supports_read_mask,omitempty"`
+	SupportsOrderBy         bool `json:"supports_order_by,omitempty"`
+	// OrderableFields are the fields by which order_by can order the
+	// results (aep.dev/132). If empty, any field may be used.
+	OrderableFields []string `json:"orderable_fields,omitempty"`
 }
 
 type DeleteMethod struct {
 	IsLongRunning bool `json:"is_long_running"`
+	// SupportsSoftDelete marks resources that are soft-deleted
+	// (aep.dev/164): deleted resources keep existing, with a delete_time
+	// and an expire_time, until they are purged or undeleted.
+	SupportsSoftDelete   bool `json:"supports_soft_delete,omitempty"`
+	SupportsValidateOnly bool `json:"supports_validate_only,omitempty"`
+	SupportsRequestId    bool `json:"supports_request_id,omitempty"`
+}
+
+type UndeleteMethod struct {
+	IsLongRunning bool `json:"is_long_running"`
+}
+
+type ListRevisionsMethod struct {
+}
+
+type GetRevisionMethod struct {
+}
+
+// CommitMethod creates a new revision from the current state of the
+// resource.
+type CommitMethod struct {
+	IsLongRunning bool `json:"is_long_running"`
+}
+
+// RollbackMethod restores the resource to a previous revision, which
+// creates a new revision.
+type RollbackMethod struct {
+	IsLongRunning bool `json:"is_long_running"`
+}
+
+// TagRevisionMethod adds a tag to a revision, by which the revision
+// can be addressed in place of its id.
+type TagRevisionMethod struct {
+}
+
+type BatchGetMethod struct {
+}
+
+// BatchCreateMethod requires the resource to have a Create method, whose
+// request is repeated in the batch request.
+type BatchCreateMethod struct {
+	IsLongRunning bool `json:"is_long_running"`
+}
+
+// BatchUpdateMethod requires the resource to have an Update method, whose
+// request is repeated in the batch request.
+type BatchUpdateMethod struct {
+	IsLongRunning bool `json:"is_long_running"`
+}
+
+type BatchDeleteMethod struct {
+	IsLongRunning bool `json:"is_long_running"`
 }
 
 type CustomMethod struct {
@@ -74,10 +218,48 @@ type CustomMethod struct {
 	IsLongRunning bool `json:"is_long_running"`
 }
 
+// GetPattern returns the primary pattern of the resource.
 func (r *Resource) GetPattern() string {
 	return strings.Join(r.PatternElems(), "/")
 }
 
+// SupportsSoftDelete returns true if deleted resources are soft-deleted
+// (aep.dev/164).
+func (r *Resource) SupportsSoftDelete() bool {
+	return r.Methods.Delete != nil && r.Methods.Delete.SupportsSoftDelete
+}
+
+// SupportsRevisions returns true if the resource has any of the
+// revision methods (aep.dev/162).
+func (r *Resource) SupportsRevisions() bool {
+	m := r.Methods
+	return m.ListRevisions != nil || m.GetRevision != nil || m.Commit != nil ||
+		m.Rollback != nil || m.TagRevision != nil
+}
+
+// GetPatterns returns all the patterns of the resource.
+func (r *Resource) GetPatterns() []string {
+	patterns := []string{}
+	for _, elems := range r.AllPatternElems() {
+		patterns = append(patterns, strings.Join(elems, "/"))
+	}
+	return patterns
+}
+
+// collectionPatterns returns the patterns of the collections that
+// contain the resource (e.g. publishers/{publisher_id}/books).
+func (r *Resource) collectionPatterns() []string {
+	patterns := []string{}
+	if r.Singleton {
+		// a singleton is not part of a collection.
+		return patterns
+	}
+	for _, elems := range r.AllPatternElems() {
+		patterns = append(patterns, strings.Join(elems[:len(elems)-1], "/"))
+	}
+	return patterns
+}
+
 // return the parent resources of the resource.
 //
 // This function should only be called until after
@@ -100,34 +282,102 @@ func (r *Resource) ParentResources() []*Resource {
 // the name of the previous parent
 // e.g:
 // - book-editions becomes editions under the parent resource book.
+//
+// For resources with multiple parents, the name is relative to the
+// first parent. Use CollectionNameForParent for the others.
 func CollectionName(r *Resource) string {
+	parents := r.ParentResources()
+	if len(parents) > 0 {
+		return CollectionNameForParent(r, parents[0])
+	}
+	return CollectionNameForParent(r, nil)
+}
+
+// CollectionNameForParent returns the collection name of the resource
+// when nested under parent, deduplicating the parent's name. parent
+// may be nil for top-level collections.
+//
+// Singletons are not part of a collection: the singular name is used
+// instead (e.g. publisher-config becomes config).
+func CollectionNameForParent(r *Resource, parent *Resource) string {
 	collectionName := r.Plural
-	if len(r.Parents) > 0 {
-		parent := r.ParentResources()[0].Singular
+	if r.Singleton {
+		collectionName = r.Singular
+	}
+	if parent != nil {
 		// if collectionName has a prefix of parent, remove it
-		if strings.HasPrefix(collectionName, parent) {
-			collectionName = strings.TrimPrefix(collectionName, parent+"-")
+		if strings.HasPrefix(collectionName, parent.Singular) {
+			collectionName = strings.TrimPrefix(collectionName, parent.Singular+"-")
 		}
 	}
 	// Convert to kebab-case for path elements
 	return cases.SnakeToKebabCase(collectionName)
 }
 
-// GeneratePatternStrings generates the pattern strings for a resource
-// TODO(yft): support multiple parents
+// PatternElems returns the elements of the primary pattern of the
+// resource.
 func (r *Resource) PatternElems() []string {
+	return r.AllPatternElems()[0]
+}
+
+// AllPatternElems returns the elements of every pattern of the
+// resource, starting with the primary pattern.
+//
+// Unless the patterns are set explicitly, a resource has a pattern
+// for each pattern of each of its parents.
+func (r *Resource) AllPatternElems() [][]string {
+	if len(r.Patterns) > 0 {
+		allElems := [][]string{}
+		for _, p := range r.Patterns {
+			allElems = append(allElems, strings.Split(strings.TrimPrefix(p, "/"), "/"))
+		}
+		return allElems
+	}
 	if len(r.patternElems) == 0 {
 		// Convert kebab-case singular to snake_case for path variables
 		singularSnake := cases.KebabToSnakeCase(r.Singular)
-		// Base pattern without params
-		patternElems := []string{CollectionName(r), fmt.Sprintf("{%s_id}", singularSnake)}
-		if len(r.Parents) > 0 {
-			patternElems = append(
-				r.ParentResources()[0].PatternElems(),
-				patternElems...,
-			)
+		idElem := fmt.Sprintf("{%s_id}", singularSnake)
+		parents := r.ParentResources()
+		if len(parents) == 0 {
+			r.patternElems = [][]string{r.appendPatternSuffix([]string{}, nil, idElem)}
+		}
+		for _, parent := range parents {
+			for _, parentElems := range parent.AllPatternElems() {
+				r.patternElems = append(r.patternElems,
+					r.appendPatternSuffix(slices.Clone(parentElems), parent, idElem))
+			}
 		}
-		r.patternElems = patternElems
 	}
 	return r.patternElems
 }
+
+// appendPatternSuffix appends the elements identifying the resource
+// under parent to elems: the collection and the id, or only the
+// singular name for singletons.
+func (r *Resource) appendPatternSuffix(elems []string, parent *Resource, idElem string) []string {
+	if r.Singleton {
+		return append(elems, CollectionNameForParent(r, parent))
+	}
+	return append(elems, CollectionNameForParent(r, parent), idElem)
+}
+
+// hasEquivalentPattern returns true if the resource has a pattern
+// matching elems, ignoring the names of the pattern variables.
+func (r *Resource) hasEquivalentPattern(elems []string) bool {
+	for _, existing := range r.AllPatternElems() {
+		if len(existing) != len(elems) {
+			continue
+		}
+		equivalent := true
+		for i := range existing {
+			if i%2 == 0 && existing[i] != elems[i] {
+				equivalent = false
+				break
+			}
+		}
+		if equivalent {
+			return true
+		}
+	}
+	return false
+}
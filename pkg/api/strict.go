@@ -0,0 +1,217 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/aep-dev/aep-lib-go/pkg/yamlutil"
+)
+
+// DecodeError is a single problem found while strictly decoding an
+// API definition.
+type DecodeError struct {
+	// Path is the dotted path of the offending key, e.g.
+	// "resources.book.methods.reed".
+	Path string
+	// Position is the location of the offending value, when the
+	// definition was loaded from YAML.
+	Position *yamlutil.Position
+	Message  string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Position != nil {
+		return fmt.Sprintf("%v (%s): %s", *e.Position, e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// DecodeErrors is the list of problems found while strictly decoding
+// an API definition.
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	lines := []string{fmt.Sprintf("%d problem(s) found in API definition:", len(e))}
+	for _, err := range e {
+		lines = append(lines, "  - "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+var schemaType = reflect.TypeOf(openapi.Schema{})
+
+// aliasesByType lists the alternate keys accepted for a type, mapped
+// to the canonical key.
+var aliasesByType = map[reflect.Type]map[string]string{
+	reflect.TypeOf(Methods{}): methodAliases,
+}
+
+// checkStrict walks the JSON definition in data against the API type,
+// and returns DecodeErrors listing all unknown keys and values of the
+// wrong type.
+func checkStrict(data []byte, sm yamlutil.SourceMap) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	c := &strictChecker{sourceMap: sm}
+	c.check(v, reflect.TypeOf(API{}), "")
+	if len(c.errs) > 0 {
+		return c.errs
+	}
+	return nil
+}
+
+type strictChecker struct {
+	sourceMap yamlutil.SourceMap
+	errs      DecodeErrors
+}
+
+func (c *strictChecker) addError(path string, format string, args ...any) {
+	e := &DecodeError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if c.sourceMap != nil {
+		if pos, ok := c.sourceMap.Lookup(path); ok {
+			e.Position = &pos
+		}
+	}
+	c.errs = append(c.errs, e)
+}
+
+func (c *strictChecker) check(v interface{}, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if v == nil {
+		return
+	}
+	if t == schemaType {
+		// schemas are open-ended, so only check that they decode.
+		c.checkDecodes(v, t, path)
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			c.addError(path, "expected an object, got %s", jsonKind(v))
+			return
+		}
+		fields := jsonFields(t)
+		aliases := aliasesByType[t]
+		for _, key := range sortedKeys(obj) {
+			name := key
+			if canonical, ok := aliases[key]; ok {
+				if _, ok := obj[canonical]; ok {
+					c.addError(joinPath(path, key), "%q is an alias of %q, which is also defined", key, canonical)
+					continue
+				}
+				name = canonical
+			}
+			field, ok := fields[strings.ToLower(name)]
+			if !ok {
+				c.addError(joinPath(path, key), "unknown key %q", key)
+				continue
+			}
+			c.check(obj[key], field.Type, joinPath(path, key))
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			c.addError(path, "expected an object, got %s", jsonKind(v))
+			return
+		}
+		for _, key := range sortedKeys(obj) {
+			c.check(obj[key], t.Elem(), joinPath(path, key))
+		}
+	case reflect.Slice:
+		arr, ok := v.([]interface{})
+		if !ok {
+			c.addError(path, "expected an array, got %s", jsonKind(v))
+			return
+		}
+		for i, elem := range arr {
+			c.check(elem, t.Elem(), joinPath(path, strconv.Itoa(i)))
+		}
+	default:
+		c.checkDecodes(v, t, path)
+	}
+}
+
+func (c *strictChecker) checkDecodes(v interface{}, t reflect.Type, path string) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.addError(path, "%v", err)
+		return
+	}
+	if err := json.Unmarshal(data, reflect.New(t).Interface()); err != nil {
+		c.addError(path, "expected %v, got %s", t, jsonKind(v))
+	}
+}
+
+// jsonFields returns the fields of a struct type that can be decoded
+// from JSON, keyed by their lower-cased JSON name. encoding/json
+// matches keys case-insensitively, so the keys are lower-cased.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fields[strings.ToLower(name)] = f
+	}
+	return fields
+}
+
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, elem string) string {
+	if path == "" {
+		return elem
+	}
+	return path + "." + elem
+}
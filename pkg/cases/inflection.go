@@ -0,0 +1,184 @@
+package cases
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// inflectionRule rewrites the end of a word matched by pattern.
+type inflectionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func rule(pattern, replacement string) inflectionRule {
+	return inflectionRule{
+		pattern:     regexp.MustCompile(pattern),
+		replacement: replacement,
+	}
+}
+
+// pluralRules and singularRules are evaluated in order, and the first
+// matching rule wins. Words that match no rule get an "s" appended or
+// removed.
+var pluralRules = []inflectionRule{
+	rule(`(quiz)$`, "${1}zes"),
+	rule(`sis$`, "ses"),
+	rule(`(s|x|z|ch|sh)$`, "${1}es"),
+	rule(`([^aeiouy]|qu)y$`, "${1}ies"),
+	rule(`(wol|hal|shel|cal|sel|el|loa|lea|thie|shea)f$`, "${1}ves"),
+	rule(`(kni|wi|li)fe$`, "${1}ves"),
+	rule(`(her|potat|tomat|ech|vet)o$`, "${1}oes"),
+}
+
+var singularRules = []inflectionRule{
+	rule(`(quiz)zes$`, "${1}"),
+	rule(`(analy|diagno|parenthe|progno|synop|the|hypothe|cri)ses$`, "${1}sis"),
+	rule(`(stat|b|camp|vir|cens|bon|foc|radi|octop|syllab|nex|ap|corp|consens|prospect)uses$`, "${1}us"),
+	rule(`(alias|lens|canvas|gas)es$`, "${1}"),
+	rule(`(ss|us|is)$`, "${1}"),
+	rule(`(x|ch|sh|ss|zz)es$`, "${1}"),
+	rule(`([^aeiouy]|qu)ies$`, "${1}y"),
+	rule(`(wol|hal|shel|cal|sel|el|loa|lea|thie|shea)ves$`, "${1}f"),
+	rule(`(kni|wi|li)ves$`, "${1}fe"),
+	rule(`(her|potat|tomat|ech|vet)oes$`, "${1}o"),
+}
+
+var (
+	inflectionsMu sync.RWMutex
+	// irregularPlurals maps a singular to its plural form, and
+	// irregularSingulars the other way around.
+	irregularPlurals   = map[string]string{}
+	irregularSingulars = map[string]string{}
+	uncountables       = map[string]bool{}
+)
+
+func init() {
+	for singular, plural := range map[string]string{
+		"person":    "people",
+		"man":       "men",
+		"woman":     "women",
+		"child":     "children",
+		"mouse":     "mice",
+		"goose":     "geese",
+		"foot":      "feet",
+		"tooth":     "teeth",
+		"ox":        "oxen",
+		"criterion": "criteria",
+		"cache":     "caches",
+		"niche":     "niches",
+		"cookie":    "cookies",
+		"movie":     "movies",
+		"tie":       "ties",
+		"zombie":    "zombies",
+	} {
+		AddIrregular(singular, plural)
+	}
+	AddUncountable(
+		"data", "deer", "equipment", "feedback", "firmware", "fish",
+		"hardware", "information", "metadata", "money", "news", "rice",
+		"series", "sheep", "software", "species", "traffic",
+	)
+}
+
+// AddIrregular registers an irregular singular / plural pair, which
+// takes precedence over the built-in rules. The singular may be a
+// multi-word name (e.g. "book-edition"), in which case it only applies
+// to that exact name.
+func AddIrregular(singular, plural string) {
+	inflectionsMu.Lock()
+	defer inflectionsMu.Unlock()
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	delete(uncountables, singular)
+	delete(uncountables, plural)
+	irregularPlurals[singular] = plural
+	irregularSingulars[plural] = singular
+}
+
+// AddUncountable registers words whose singular and plural forms are
+// identical (e.g. "metadata").
+func AddUncountable(words ...string) {
+	inflectionsMu.Lock()
+	defer inflectionsMu.Unlock()
+	for _, w := range words {
+		uncountables[strings.ToLower(w)] = true
+	}
+}
+
+// Pluralize returns the plural form of an English noun.
+//
+// Multi-word names in kebab-case or snake_case have their last word
+// pluralized, e.g. "book-edition" becomes "book-editions".
+func Pluralize(s string) string {
+	return inflect(s, irregularPlurals, pluralRules, func(w string) string {
+		return w + "s"
+	})
+}
+
+// Singularize returns the singular form of an English noun, such as
+// the collection segment of a resource path.
+//
+// Multi-word names in kebab-case or snake_case have their last word
+// singularized, e.g. "book-editions" becomes "book-edition".
+func Singularize(s string) string {
+	return inflect(s, irregularSingulars, singularRules, func(w string) string {
+		return strings.TrimSuffix(w, "s")
+	})
+}
+
+func inflect(s string, irregulars map[string]string, rules []inflectionRule, fallback func(string) string) string {
+	if s == "" {
+		return s
+	}
+	inflectionsMu.RLock()
+	defer inflectionsMu.RUnlock()
+	// overrides of the full name take precedence over the last word.
+	if result, ok := lookupOverride(s, irregulars); ok {
+		return result
+	}
+	prefix, word := "", s
+	if i := strings.LastIndexAny(s, "-_"); i >= 0 {
+		prefix, word = s[:i+1], s[i+1:]
+	}
+	// a trailing separator leaves no word to inflect.
+	if word == "" {
+		return s
+	}
+	if result, ok := lookupOverride(word, irregulars); ok {
+		return prefix + result
+	}
+	lower := strings.ToLower(word)
+	for _, r := range rules {
+		if r.pattern.MatchString(lower) {
+			return prefix + matchCase(word, r.pattern.ReplaceAllString(lower, r.replacement))
+		}
+	}
+	return prefix + matchCase(word, fallback(lower))
+}
+
+// lookupOverride returns the uncountable or irregular form of word.
+func lookupOverride(word string, irregulars map[string]string) (string, bool) {
+	lower := strings.ToLower(word)
+	if uncountables[lower] {
+		return word, true
+	}
+	if result, ok := irregulars[lower]; ok {
+		return matchCase(word, result), true
+	}
+	return "", false
+}
+
+// matchCase applies the capitalization of original to word, which is
+// lower case.
+func matchCase(original, word string) string {
+	switch {
+	case original == "":
+		return word
+	case original == strings.ToUpper(original) && len(original) > 1:
+		return strings.ToUpper(word)
+	case original[:1] == strings.ToUpper(original[:1]):
+		return UpperFirst(word)
+	}
+	return word
+}
@@ -0,0 +1,106 @@
+package cases
+
+import "testing"
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty string", "", ""},
+		{"regular", "book", "books"},
+		{"consonant y", "policy", "policies"},
+		{"vowel y", "key", "keys"},
+		{"s", "status", "statuses"},
+		{"ss", "address", "addresses"},
+		{"x", "index", "indexes"},
+		{"ch", "match", "matches"},
+		{"sis", "analysis", "analyses"},
+		{"f", "shelf", "shelves"},
+		{"fe", "knife", "knives"},
+		{"o", "hero", "heroes"},
+		{"irregular", "person", "people"},
+		{"irregular only matches whole words", "human", "humans"},
+		{"uncountable", "metadata", "metadata"},
+		{"kebab case", "book-edition", "book-editions"},
+		{"trailing separator", "book-", "book-"},
+		{"kebab case irregular", "sales-person", "sales-people"},
+		{"snake case", "access_policy", "access_policies"},
+		{"capitalized", "Policy", "Policies"},
+		{"upper case", "CHILD", "CHILDREN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Pluralize(tt.input)
+			if got != tt.expected {
+				t.Errorf("Pluralize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty string", "", ""},
+		{"regular", "books", "book"},
+		{"ies", "policies", "policy"},
+		{"vowel y", "keys", "key"},
+		{"uses", "statuses", "status"},
+		{"ending in use", "houses", "house"},
+		{"ending in se", "databases", "database"},
+		{"sses", "addresses", "address"},
+		{"xes", "indexes", "index"},
+		{"ches", "matches", "match"},
+		{"ses", "analyses", "analysis"},
+		{"ves", "shelves", "shelf"},
+		{"ending in ve", "archives", "archive"},
+		{"oes", "heroes", "hero"},
+		{"irregular", "people", "person"},
+		{"uncountable", "series", "series"},
+		{"already singular", "status", "status"},
+		{"kebab case", "book-editions", "book-edition"},
+		{"trailing separator", "books_", "books_"},
+		{"snake case", "access_policies", "access_policy"},
+		{"capitalized", "Children", "Child"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Singularize(tt.input)
+			if got != tt.expected {
+				t.Errorf("Singularize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInflectionOverrides(t *testing.T) {
+	AddIrregular("cactus", "cacti")
+	AddIrregular("book-edition", "book-printings")
+	AddUncountable("aircraft")
+
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{"cactus", "cacti"},
+		{"garden-cactus", "garden-cacti"},
+		{"book-edition", "book-printings"},
+		{"rare-book-edition", "rare-book-editions"},
+		{"aircraft", "aircraft"},
+	}
+	for _, tt := range tests {
+		if got := Pluralize(tt.singular); got != tt.plural {
+			t.Errorf("Pluralize(%q) = %q, want %q", tt.singular, got, tt.plural)
+		}
+		if got := Singularize(tt.plural); got != tt.singular {
+			t.Errorf("Singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+		}
+	}
+}
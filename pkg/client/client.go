@@ -3,15 +3,22 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
 	"github.com/aep-dev/aep-lib-go/pkg/cases"
+	"github.com/aep-dev/aep-lib-go/pkg/constants"
 )
 
+// maxReadModifyWriteAttempts bounds the retries of ReadModifyWrite.
+const maxReadModifyWriteAttempts = 5
+
 type RequestLoggingFunction func(ctx context.Context, req *http.Request, args ...any)
 type ResponseLoggingFunction func(ctx context.Context, resp *http.Response, args ...any)
 
@@ -20,6 +27,20 @@ type Client struct {
 	client                  *http.Client
 	RequestLoggingFunction  RequestLoggingFunction
 	ResponseLoggingFunction ResponseLoggingFunction
+	// OperationBackoff is the delay between polls of long-running
+	// operations.
+	OperationBackoff Backoff
+	// RetryPolicy configures the retries of requests that failed
+	// transiently. Requests are not retried by default.
+	RetryPolicy RetryPolicy
+	// Interceptors intercept every request sent by the client, see Use.
+	Interceptors []Interceptor
+	// RequestValidation validates the bodies of the requests of methods
+	// called with a resource, such as Create, against its schema, and
+	// those of custom methods against their request schema.
+	// Invalid bodies are rejected with ValidationErrors, without being
+	// sent. Bodies are not validated by default.
+	RequestValidation ValidationMode
 }
 
 func NewClient(c *http.Client) *Client {
@@ -29,10 +50,15 @@ func NewClient(c *http.Client) *Client {
 		// The basic logging function does not do anything.
 		RequestLoggingFunction:  func(ctx context.Context, req *http.Request, args ...any) {},
 		ResponseLoggingFunction: func(ctx context.Context, resp *http.Response, args ...any) {},
+		OperationBackoff:        DefaultBackoff,
 	}
 }
 
-func (c *Client) Create(ctx context.Context, r *api.Resource, serverUrl string, body map[string]interface{}, parameters map[string]string) (map[string]interface{}, error) {
+func (c *Client) Create(ctx context.Context, r *api.Resource, serverUrl string, body map[string]interface{}, parameters map[string]string, opts ...CallOption) (map[string]interface{}, error) {
+	body, err := c.validateRequest(r.Schema, body, false, constants.FIELD_ID_NAME)
+	if err != nil {
+		return nil, err
+	}
 	suffix := ""
 	if r.Methods.Create != nil && r.Methods.Create.SupportsUserSettableCreate {
 		id, ok := body["id"]
@@ -48,6 +74,13 @@ func (c *Client) Create(ctx context.Context, r *api.Resource, serverUrl string,
 	if err != nil {
 		return nil, err
 	}
+	o := newCallOptions(opts)
+	if r.Methods.Create != nil && r.Methods.Create.SupportsRequestId && o.requestId == "" {
+		// the id is generated once, so that retries of the request
+		// reuse it.
+		o.requestId = NewRequestId()
+	}
+	url = withQuery(url, o.query())
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -59,25 +92,145 @@ func (c *Client) Create(ctx context.Context, r *api.Resource, serverUrl string,
 		return nil, fmt.Errorf("error creating POST request: %v", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(ctx, &CallInfo{Resource: r, Method: "Create"}, req)
 	if err != nil {
 		return nil, err
 	}
-	return c.parseResponse(ctx, resp)
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return c.wait(ctx, r, serverUrl, m, o, r.Methods.Create != nil && r.Methods.Create.IsLongRunning)
 }
 
-func (c *Client) List(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string) ([]map[string]interface{}, error) {
+// List returns the first page of the resources of a collection. Use
+// ListAll to iterate over all of them.
+func (c *Client) List(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, opts ...CallOption) ([]map[string]interface{}, error) {
+	o := newCallOptions(opts)
+	if err := o.validateList(r); err != nil {
+		return nil, err
+	}
 	url, err := basePath(ctx, r, serverUrl, parameters, "")
 	if err != nil {
 		return nil, err
 	}
+	page, err := c.listPage(ctx, r, withQuery(url, o.query()))
+	if err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
+
+// ListPage is a page of the resources of a collection.
+type ListPage struct {
+	Results []map[string]interface{}
+	// Unreachable are the paths of the resources that could not be
+	// listed, e.g. because their location is unavailable (aep.dev/217).
+	Unreachable []string
+	// NextPageToken retrieves the next page, and is empty on the last
+	// page.
+	NextPageToken string
+}
+
+// UnreachableError is returned by ListAll after all the resources that
+// could be listed, if some could not be (aep.dev/217).
+type UnreachableError struct {
+	Paths []string
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("%d resources were unreachable: %s", len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// ListPages iterates over the pages of a collection (aep.dev/158),
+// following the page tokens of the service. The options of the call
+// (e.g. MaxPageSize, Filter or OrderBy) are sent with every page, except
+// Skip, which only applies to the first page.
+//
+// Iteration stops after the first error.
+func (c *Client) ListPages(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, opts ...CallOption) iter.Seq2[*ListPage, error] {
+	return func(yield func(*ListPage, error) bool) {
+		o := newCallOptions(opts)
+		if err := o.validateList(r); err != nil {
+			yield(nil, err)
+			return
+		}
+		url, err := basePath(ctx, r, serverUrl, parameters, "")
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		query := o.query()
+		for {
+			page, err := c.listPage(ctx, r, withQuery(url, query))
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) || page.NextPageToken == "" {
+				return
+			}
+			if page.NextPageToken == query.Get(constants.FIELD_PAGE_TOKEN_NAME) {
+				yield(nil, fmt.Errorf("the page token %q was returned twice", page.NextPageToken))
+				return
+			}
+			query.Del(constants.FIELD_SKIP_NAME)
+			query.Set(constants.FIELD_PAGE_TOKEN_NAME, page.NextPageToken)
+		}
+	}
+}
+
+// ListAll iterates over all the resources of a collection, fetching
+// pages as needed, e.g.
+//
+//	for book, err := range c.ListAll(ctx, r, serverUrl, parameters, MaxItems(100)) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Breaking out of the loop stops fetching pages. MaxItems bounds the
+// number of resources. If some resources were unreachable, an
+// *UnreachableError listing them is yielded after the others.
+func (c *Client) ListAll(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, opts ...CallOption) iter.Seq2[map[string]interface{}, error] {
+	return func(yield func(map[string]interface{}, error) bool) {
+		maxItems := newCallOptions(opts).maxItems
+		count := 0
+		unreachable := []string{}
+		for page, err := range c.ListPages(ctx, r, serverUrl, parameters, opts...) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			unreachable = append(unreachable, page.Unreachable...)
+			for _, resource := range page.Results {
+				if maxItems > 0 && count >= maxItems {
+					return
+				}
+				count++
+				if !yield(resource, nil) {
+					return
+				}
+			}
+			if maxItems > 0 && count >= maxItems {
+				return
+			}
+		}
+		if len(unreachable) > 0 {
+			yield(nil, &UnreachableError{Paths: unreachable})
+		}
+	}
+}
 
+// listPage fetches the page of the collection of r at url.
+func (c *Client) listPage(ctx context.Context, r *api.Resource, url string) (*ListPage, error) {
 	req, err := c.newRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating GET request: %v", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(ctx, &CallInfo{Resource: r, Method: "List"}, req)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +240,16 @@ func (c *Client) List(ctx context.Context, r *api.Resource, serverUrl string, pa
 		return nil, err
 	}
 
+	page := &ListPage{}
+	if token, ok := m[constants.FIELD_NEXT_PAGE_TOKEN_NAME].(string); ok {
+		page.NextPageToken = token
+	}
+	if unreachable, ok := m[constants.FIELD_UNREACHABLE_NAME].([]interface{}); ok {
+		for _, u := range unreachable {
+			page.Unreachable = append(page.Unreachable, fmt.Sprint(u))
+		}
+	}
+
 	kebab := cases.KebabToCamelCase(r.Plural)
 	lowerKebab := ""
 	if len(kebab) > 1 {
@@ -95,13 +258,12 @@ func (c *Client) List(ctx context.Context, r *api.Resource, serverUrl string, pa
 	for _, key := range []string{"results", r.Plural, cases.KebabToCamelCase(r.Plural), lowerKebab} {
 		if val, ok := m[key]; ok {
 			if arr, ok := val.([]interface{}); ok {
-				var result []map[string]interface{}
 				for _, v := range arr {
 					if m, ok := v.(map[string]interface{}); ok {
-						result = append(result, m)
+						page.Results = append(page.Results, m)
 					}
 				}
-				return result, nil
+				return page, nil
 			}
 		}
 	}
@@ -109,15 +271,21 @@ func (c *Client) List(ctx context.Context, r *api.Resource, serverUrl string, pa
 	return nil, fmt.Errorf("no valid list key was found")
 }
 
-func (c *Client) Get(ctx context.Context, serverUrl string, path string) (map[string]interface{}, error) {
+func (c *Client) Get(ctx context.Context, serverUrl string, path string, opts ...CallOption) (map[string]interface{}, error) {
+	return c.get(ctx, &CallInfo{Method: "Get"}, serverUrl, path, opts...)
+}
+
+// get gets the resource or operation at path.
+func (c *Client) get(ctx context.Context, info *CallInfo, serverUrl string, path string, opts ...CallOption) (map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/%s", serverUrl, strings.TrimPrefix(path, "/"))
+	url = withQuery(url, newCallOptions(opts).query())
 
 	req, err := c.newRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating GET request: %v", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(ctx, info, req)
 	if err != nil {
 		return nil, err
 	}
@@ -125,47 +293,456 @@ func (c *Client) Get(ctx context.Context, serverUrl string, path string) (map[st
 	return c.parseResponse(ctx, resp)
 }
 
-func (c *Client) Delete(ctx context.Context, serverUrl string, path string) error {
+func (c *Client) Delete(ctx context.Context, serverUrl string, path string, opts ...CallOption) error {
+	return c.DeleteIfMatch(ctx, serverUrl, path, "", opts...)
+}
+
+// DeleteIfMatch deletes the resource at path, if its etag matches etag
+// (aep.dev/154). It returns an error wrapping ErrPreconditionFailed
+// otherwise. An empty etag deletes the resource unconditionally.
+func (c *Client) DeleteIfMatch(ctx context.Context, serverUrl string, path string, etag string, opts ...CallOption) error {
+	return c.delete(ctx, &CallInfo{Method: "Delete"}, serverUrl, path, etag, opts...)
+}
+
+// delete deletes the resource at path, if its etag matches a non-empty
+// etag.
+func (c *Client) delete(ctx context.Context, info *CallInfo, serverUrl string, path string, etag string, opts ...CallOption) error {
+	o := newCallOptions(opts)
 	url := fmt.Sprintf("%s/%s", serverUrl, strings.TrimPrefix(path, "/"))
+	url = withQuery(url, o.query())
 
 	req, err := c.newRequest(ctx, "DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("error creating DELETE request: %v", err)
 	}
+	setIfMatch(req, etag)
+
+	resp, err := c.do(ctx, info, req)
+	if err != nil {
+		return err
+	}
 
-	resp, err := c.client.Do(req)
+	m, err := c.parseResponse(ctx, resp)
 	if err != nil {
 		return err
 	}
+	longRunning := looksLikeOperation(m)
+	if r := info.Resource; r != nil {
+		longRunning = r.Methods.Delete != nil && r.Methods.Delete.IsLongRunning
+	}
+	_, err = c.wait(ctx, info.Resource, serverUrl, m, o, longRunning)
+	return err
+}
+
+// Undelete restores the soft-deleted resource at path (aep.dev/164),
+// and returns it.
+func (c *Client) Undelete(ctx context.Context, serverUrl string, path string, opts ...CallOption) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/%s:undelete", serverUrl, strings.TrimPrefix(path, "/"))
+
+	req, err := c.newRequest(ctx, "POST", url, strings.NewReader("{}"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating POST request: %v", err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Method: "Undelete"}, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return c.wait(ctx, nil, serverUrl, m, newCallOptions(opts), looksLikeOperation(m))
+}
+
+// ListRevisions returns the revisions of the resource at path
+// (aep.dev/162).
+func (c *Client) ListRevisions(ctx context.Context, serverUrl string, path string) ([]map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/%s/revisions", serverUrl, strings.TrimPrefix(path, "/"))
+
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request: %v", err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Method: "ListRevisions"}, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(m)
+}
+
+// GetRevision returns the revision revisionId of the resource at path
+// (aep.dev/162). revisionId may also be a tag of the revision.
+func (c *Client) GetRevision(ctx context.Context, serverUrl string, path string, revisionId string) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/%s@%s", serverUrl, strings.TrimPrefix(path, "/"), revisionId)
+
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request: %v", err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Method: "GetRevision"}, req)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err = c.parseResponse(ctx, resp)
+	return c.parseResponse(ctx, resp)
+}
+
+func (c *Client) Update(ctx context.Context, serverUrl string, path string, body map[string]interface{}, opts ...CallOption) error {
+	_, err := c.update(ctx, &CallInfo{Method: "Update"}, serverUrl, path, body, "", opts...)
 	return err
 }
 
-func (c *Client) Update(ctx context.Context, serverUrl string, path string, body map[string]interface{}) error {
+// UpdateIfMatch updates the resource at path, if its etag matches etag
+// (aep.dev/154). It returns an error wrapping ErrPreconditionFailed
+// otherwise.
+func (c *Client) UpdateIfMatch(ctx context.Context, serverUrl string, path string, body map[string]interface{}, etag string, opts ...CallOption) error {
+	_, err := c.update(ctx, &CallInfo{Method: "Update"}, serverUrl, path, body, etag, opts...)
+	return err
+}
+
+// ReadModifyWrite reads the resource at path, applies modify to it, and
+// writes it back with an If-Match header carrying the etag that was
+// read (aep.dev/154). If the resource was changed concurrently, the
+// whole cycle is retried, up to maxReadModifyWriteAttempts times. The
+// updated resource is returned.
+func (c *Client) ReadModifyWrite(ctx context.Context, serverUrl string, path string, modify func(resource map[string]interface{}) error) (map[string]interface{}, error) {
+	var err error
+	for attempt := 0; attempt < maxReadModifyWriteAttempts; attempt++ {
+		resource, getErr := c.Get(ctx, serverUrl, path)
+		if getErr != nil {
+			return nil, getErr
+		}
+		etag, ok := resource[constants.FIELD_ETAG_NAME].(string)
+		if !ok || etag == "" {
+			return nil, fmt.Errorf("resource %s has no etag", path)
+		}
+		if err := modify(resource); err != nil {
+			return nil, err
+		}
+		var updated map[string]interface{}
+		updated, err = c.update(ctx, &CallInfo{Method: "Update"}, serverUrl, path, resource, etag)
+		if !errors.Is(err, ErrPreconditionFailed) {
+			return updated, err
+		}
+	}
+	return nil, fmt.Errorf("resource %s was modified concurrently %d times: %w", path, maxReadModifyWriteAttempts, err)
+}
+
+func (c *Client) update(ctx context.Context, info *CallInfo, serverUrl string, path string, body map[string]interface{}, etag string, opts ...CallOption) (map[string]interface{}, error) {
+	o := newCallOptions(opts)
 	url := fmt.Sprintf("%s/%s", serverUrl, strings.TrimPrefix(path, "/"))
+	url = withQuery(url, o.query())
 
 	reqBody, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("error marshalling JSON for request body: %v", err)
+		return nil, fmt.Errorf("error marshalling JSON for request body: %v", err)
 	}
 
 	req, err := c.newRequest(ctx, "PATCH", url, strings.NewReader(string(reqBody)))
 	if err != nil {
-		return fmt.Errorf("error creating PATCH request: %v", err)
+		return nil, fmt.Errorf("error creating PATCH request: %v", err)
 	}
+	setIfMatch(req, etag)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(ctx, info, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = c.parseResponse(ctx, resp)
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	longRunning := looksLikeOperation(m)
+	if r := info.Resource; r != nil {
+		longRunning = r.Methods.Update != nil && r.Methods.Update.IsLongRunning
+	}
+	return c.wait(ctx, info.Resource, serverUrl, m, o, longRunning)
+}
+
+// Apply creates or replaces the resource at path with body (aep.dev/137).
+// Unlike Update, fields that are not in body are cleared.
+func (c *Client) Apply(ctx context.Context, r *api.Resource, serverUrl string, path string, body map[string]interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	if r.Methods.Apply == nil {
+		return nil, fmt.Errorf("resource %s does not support apply", r.Singular)
+	}
+	body, err := c.validateRequest(r.Schema, body, false)
+	if err != nil {
+		return nil, err
+	}
+	o := newCallOptions(opts)
+	if r.Methods.Apply.SupportsRequestId && o.requestId == "" {
+		o.requestId = NewRequestId()
+	}
+	url := fmt.Sprintf("%s/%s", serverUrl, strings.TrimPrefix(path, "/"))
+	url = withQuery(url, o.query())
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON for request body: %v", err)
+	}
+
+	req, err := c.newRequest(ctx, "PUT", url, strings.NewReader(string(reqBody)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating PUT request: %v", err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Resource: r, Method: "Apply"}, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return c.wait(ctx, r, serverUrl, m, o, r.Methods.Apply.IsLongRunning)
+}
+
+// setIfMatch makes the request conditional on the etag of the resource,
+// unless etag is empty.
+func setIfMatch(req *http.Request, etag string) {
+	if etag != "" {
+		req.Header.Set(constants.HEADER_IF_MATCH, etag)
+	}
+}
+
+// BatchGet retrieves the resources at paths in a single request
+// (aep.dev/231). The resources are returned in the order of paths.
+func (c *Client) BatchGet(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, paths []string) ([]map[string]interface{}, error) {
+	if r.Methods.BatchGet == nil {
+		return nil, fmt.Errorf("resource %s does not support batch_get", r.Singular)
+	}
+	u, err := basePath(ctx, r, serverUrl, parameters, ":batchGet")
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	for _, path := range paths {
+		query.Add(constants.FIELD_PATHS_NAME, path)
+	}
+	u = u + "?" + query.Encode()
+
+	req, err := c.newRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request: %v", err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Resource: r, Method: "BatchGet"}, req)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(m)
+}
+
+// BatchCreate creates the resources described by bodies in a single
+// request (aep.dev/233). If the resource supports user-settable ids,
+// the "id" field of each body is used as the id of the resource.
+func (c *Client) BatchCreate(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, bodies []map[string]interface{}) ([]map[string]interface{}, error) {
+	if r.Methods.BatchCreate == nil {
+		return nil, fmt.Errorf("resource %s does not support batch_create", r.Singular)
+	}
+	bodyField := cases.KebabToSnakeCase(r.Singular)
+	requests := []map[string]interface{}{}
+	for _, body := range bodies {
+		body, err := c.validateRequest(r.Schema, body, false, constants.FIELD_ID_NAME)
+		if err != nil {
+			return nil, err
+		}
+		request := map[string]interface{}{bodyField: body}
+		if r.Methods.Create != nil && r.Methods.Create.SupportsUserSettableCreate {
+			id, ok := body[constants.FIELD_ID_NAME]
+			if !ok {
+				return nil, fmt.Errorf("id field not found in %v", body)
+			}
+			request[constants.FIELD_ID_NAME] = id
+		}
+		requests = append(requests, request)
+	}
+	m, err := c.batchRequest(ctx, r, serverUrl, parameters, "batchCreate", map[string]interface{}{
+		constants.FIELD_REQUESTS_NAME: requests,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(m)
+}
+
+// BatchUpdate updates the resources described by bodies in a single
+// request (aep.dev/234). Each body must contain the path of the
+// resource to update.
+func (c *Client) BatchUpdate(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, bodies []map[string]interface{}) ([]map[string]interface{}, error) {
+	if r.Methods.BatchUpdate == nil {
+		return nil, fmt.Errorf("resource %s does not support batch_update", r.Singular)
+	}
+	bodyField := cases.KebabToSnakeCase(r.Singular)
+	requests := []map[string]interface{}{}
+	for _, body := range bodies {
+		path, ok := body[constants.FIELD_PATH_NAME]
+		if !ok {
+			return nil, fmt.Errorf("path field not found in %v", body)
+		}
+		body, err := c.validateRequest(r.Schema, body, true, constants.FIELD_PATH_NAME)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, map[string]interface{}{
+			constants.FIELD_PATH_NAME: path,
+			bodyField:                 body,
+		})
+	}
+	m, err := c.batchRequest(ctx, r, serverUrl, parameters, "batchUpdate", map[string]interface{}{
+		constants.FIELD_REQUESTS_NAME: requests,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(m)
+}
+
+// BatchDelete deletes the resources at paths in a single request
+// (aep.dev/235).
+func (c *Client) BatchDelete(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, paths []string) error {
+	if r.Methods.BatchDelete == nil {
+		return fmt.Errorf("resource %s does not support batch_delete", r.Singular)
+	}
+	_, err := c.batchRequest(ctx, r, serverUrl, parameters, "batchDelete", map[string]interface{}{
+		constants.FIELD_PATHS_NAME: paths,
+	})
 	return err
 }
 
+// batchRequest sends a POST request to the batch method name of the
+// collection of the resource.
+func (c *Client) batchRequest(ctx context.Context, r *api.Resource, serverUrl string, parameters map[string]string, name string, body map[string]interface{}) (map[string]interface{}, error) {
+	u, err := basePath(ctx, r, serverUrl, parameters, ":"+name)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON: %v", err)
+	}
+
+	req, err := c.newRequest(ctx, "POST", u, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating POST request: %v", err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Resource: r, Method: strings.ToUpper(name[:1]) + name[1:]}, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseResponse(ctx, resp)
+}
+
+// batchResults returns the resources in the results field of a batch
+// or list response.
+func batchResults(m map[string]interface{}) ([]map[string]interface{}, error) {
+	results, ok := m[constants.FIELD_RESULTS_NAME].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no valid %s key was found", constants.FIELD_RESULTS_NAME)
+	}
+	resources := []map[string]interface{}{}
+	for _, v := range results {
+		if resource, ok := v.(map[string]interface{}); ok {
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
+
+// InvokeCollection calls the collection custom method name of the
+// resource (e.g. POST /publishers/my-pub/books:search). The body is
+// only sent for POST methods.
+func (c *Client) InvokeCollection(ctx context.Context, r *api.Resource, serverUrl string, name string, parameters map[string]string, body map[string]interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	cm := findCustomMethod(r.CollectionCustomMethods, name)
+	if cm == nil {
+		return nil, fmt.Errorf("collection custom method %q not found for resource %s", name, r.Singular)
+	}
+	url, err := basePath(ctx, r, serverUrl, parameters, ":"+name)
+	if err != nil {
+		return nil, err
+	}
+	return c.invokeCustomMethod(ctx, r, cm, serverUrl, url, body, opts)
+}
+
+// Invoke calls the custom method name of the resource at path (e.g.
+// POST /publishers/my-pub/books/1:archive). The body is validated
+// against the request schema of the method, and only sent for POST
+// methods. Long-running methods are waited for, unless NoWait is
+// passed.
+func (c *Client) Invoke(ctx context.Context, r *api.Resource, serverUrl string, name string, path string, body map[string]interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	cm := findCustomMethod(r.CustomMethods, name)
+	if cm == nil {
+		return nil, fmt.Errorf("custom method %q not found for resource %s", name, r.Singular)
+	}
+	url := fmt.Sprintf("%s/%s:%s", serverUrl, strings.TrimPrefix(path, "/"), name)
+	return c.invokeCustomMethod(ctx, r, cm, serverUrl, url, body, opts)
+}
+
+func findCustomMethod(methods []*api.CustomMethod, name string) *api.CustomMethod {
+	for _, cm := range methods {
+		if cm.Name == name {
+			return cm
+		}
+	}
+	return nil
+}
+
+func (c *Client) invokeCustomMethod(ctx context.Context, r *api.Resource, cm *api.CustomMethod, serverUrl string, url string, body map[string]interface{}, opts []CallOption) (map[string]interface{}, error) {
+	o := newCallOptions(opts)
+	url = withQuery(url, o.query())
+
+	var reqBody io.Reader
+	if cm.Method == "POST" {
+		if body == nil {
+			body = map[string]interface{}{}
+		}
+		var err error
+		body, err = c.validateRequest(cm.Request, body, false)
+		if err != nil {
+			return nil, err
+		}
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling JSON: %v", err)
+		}
+		reqBody = strings.NewReader(string(jsonBody))
+	}
+
+	req, err := c.newRequest(ctx, cm.Method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s request: %v", cm.Method, err)
+	}
+
+	resp, err := c.do(ctx, &CallInfo{Resource: r, Method: cm.Name}, req)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return c.wait(ctx, r, serverUrl, m, o, cm.IsLongRunning)
+}
+
 func (c *Client) newRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("error creating %s request: %v", method, err)
 	}
@@ -176,6 +753,9 @@ func (c *Client) newRequest(ctx context.Context, method string, url string, body
 	return req, nil
 }
 
+// parseResponse decodes the body of a response. Responses with an
+// error status code, or an error in their body, are returned as an
+// *Error.
 func (c *Client) parseResponse(ctx context.Context, resp *http.Response) (map[string]interface{}, error) {
 	defer resp.Body.Close()
 	respBody, err := io.ReadAll(resp.Body)
@@ -185,6 +765,10 @@ func (c *Client) parseResponse(ctx context.Context, resp *http.Response) (map[st
 
 	c.ResponseLoggingFunction(ctx, resp)
 
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, newError(resp, respBody)
+	}
+
 	// Empty response means no errors.
 	if len(respBody) == 0 {
 		return map[string]interface{}{}, nil
@@ -193,30 +777,36 @@ func (c *Client) parseResponse(ctx context.Context, resp *http.Response) (map[st
 	var data map[string]interface{}
 	err = json.Unmarshal(respBody, &data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	err = checkErrors(data)
-	if err != nil {
-		return nil, err
+	// the error of a failed operation is returned by its handle.
+	if isOperation(data) {
+		return data, nil
 	}
 
-	return data, nil
-}
-
-func checkErrors(resp map[string]interface{}) error {
-	e, ok := resp["error"]
-	if ok {
-		return fmt.Errorf("returned errors, %v", e)
+	if _, ok := data["error"]; ok {
+		return nil, newError(resp, respBody)
 	}
-	return nil
+
+	return data, nil
 }
 
+// basePath returns the URL of the collection of the resource. For a
+// resource with multiple patterns, the first pattern whose variables
+// are all provided in parameters is used.
 func basePath(_ context.Context, r *api.Resource, serverUrl string, parameters map[string]string, suffix string) (string, error) {
 	serverUrl = strings.TrimSuffix(serverUrl, "/")
+	patternElems := r.PatternElems()
+	for _, candidate := range r.AllPatternElems() {
+		if hasPatternParameters(candidate, parameters) {
+			patternElems = candidate
+			break
+		}
+	}
 	urlElems := []string{serverUrl}
-	for i, elem := range r.PatternElems() {
-		if i == len(r.PatternElems())-1 {
+	for i, elem := range patternElems {
+		if i == len(patternElems)-1 {
 			continue
 		}
 
@@ -241,3 +831,15 @@ func basePath(_ context.Context, r *api.Resource, serverUrl string, parameters m
 	}
 	return result, nil
 }
+
+// hasPatternParameters returns true if parameters contains a value for
+// each variable of the parent portion of the pattern.
+func hasPatternParameters(patternElems []string, parameters map[string]string) bool {
+	for i := 1; i < len(patternElems)-1; i += 2 {
+		elem := patternElems[i]
+		if _, ok := parameters[elem[1:len(elem)-1]]; !ok {
+			return false
+		}
+	}
+	return true
+}
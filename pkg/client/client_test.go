@@ -2,10 +2,17 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
 	"github.com/jarcoal/httpmock"
 )
 
@@ -167,3 +174,1109 @@ func TestList(t *testing.T) {
 		t.Errorf("expected 1 item in the list, got %d", len(data))
 	}
 }
+
+func TestListWithMultiplePatterns(t *testing.T) {
+	httpmock.Activate()
+	httpmock.RegisterResponder("GET", "http://localhost:8081/authors/my-author/books",
+		httpmock.NewStringResponder(200, "{\"results\":[{\"path\":\"/authors/my-author/books/1\"}]}"))
+
+	r := &api.Resource{
+		Singular: "book",
+		Plural:   "books",
+		Patterns: []string{
+			"publishers/{publisher_id}/books/{book_id}",
+			"authors/{author_id}/books/{book_id}",
+		},
+	}
+	parameters := map[string]string{
+		"author_id": "my-author",
+	}
+
+	c := NewClient(http.DefaultClient)
+	data, err := c.List(context.Background(), r, "http://localhost:8081/", parameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 {
+		t.Errorf("expected 1 item in the list, got %d", len(data))
+	}
+}
+
+func TestInvokeCollection(t *testing.T) {
+	httpmock.Activate()
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books:search",
+		httpmock.NewStringResponder(200, "{\"results\":[{\"path\":\"/publishers/my-pub/books/1\"}]}"))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books:count",
+		httpmock.NewStringResponder(200, "{\"count\":1}"))
+
+	r := api.ExampleAPI().Resources["book"]
+	r.CollectionCustomMethods = []*api.CustomMethod{
+		{Name: "search", Method: "POST"},
+		{Name: "count", Method: "GET"},
+	}
+	parameters := map[string]string{
+		"publisher_id": "my-pub",
+	}
+
+	c := NewClient(http.DefaultClient)
+	data, err := c.InvokeCollection(context.Background(), r, "http://localhost:8081", "search", parameters, map[string]interface{}{"query": "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := data["results"]; !ok {
+		t.Errorf("expected results in the response, got %v", data)
+	}
+
+	data, err = c.InvokeCollection(context.Background(), r, "http://localhost:8081", "count", parameters, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["count"] != float64(1) {
+		t.Errorf("expected count to be 1, got %v", data["count"])
+	}
+
+	_, err = c.InvokeCollection(context.Background(), r, "http://localhost:8081", "missing", parameters, nil)
+	if err == nil {
+		t.Errorf("expected an error for an unknown custom method")
+	}
+}
+
+func TestBatchMethods(t *testing.T) {
+	httpmock.Activate()
+	r := api.ExampleAPI().Resources["book"]
+	r.Methods.BatchGet = &api.BatchGetMethod{}
+	r.Methods.BatchCreate = &api.BatchCreateMethod{}
+	r.Methods.BatchUpdate = &api.BatchUpdateMethod{}
+	r.Methods.BatchDelete = &api.BatchDeleteMethod{}
+	c := NewClient(http.DefaultClient)
+	ctx := context.Background()
+	parameters := map[string]string{
+		"publisher_id": "my-pub",
+	}
+	var requestBody map[string]interface{}
+	recordBody := func(response string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			requestBody = nil
+			if err := json.NewDecoder(req.Body).Decode(&requestBody); err != nil {
+				return nil, err
+			}
+			return httpmock.NewStringResponse(200, response), nil
+		}
+	}
+	results := "{\"results\":[{\"path\":\"/publishers/my-pub/books/1\"},{\"path\":\"/publishers/my-pub/books/2\"}]}"
+
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books:batchGet",
+		func(req *http.Request) (*http.Response, error) {
+			paths := req.URL.Query()["paths"]
+			if len(paths) != 2 || paths[0] != "publishers/my-pub/books/1" {
+				t.Errorf("unexpected paths query parameter %v", paths)
+			}
+			return httpmock.NewStringResponse(200, results), nil
+		})
+	got, err := c.BatchGet(ctx, r, "http://localhost:8081", parameters, []string{"publishers/my-pub/books/1", "publishers/my-pub/books/2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 results, got %d", len(got))
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books:batchCreate", recordBody(results))
+	got, err = c.BatchCreate(ctx, r, "http://localhost:8081", parameters, []map[string]interface{}{
+		{"id": "1", "price": 1},
+		{"id": "2", "price": 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 results, got %d", len(got))
+	}
+	requests, _ := requestBody["requests"].([]interface{})
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %v", requestBody)
+	}
+	if first := requests[0].(map[string]interface{}); first["id"] != "1" || first["book"] == nil {
+		t.Errorf("unexpected create request %v", first)
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books:batchUpdate", recordBody(results))
+	_, err = c.BatchUpdate(ctx, r, "http://localhost:8081", parameters, []map[string]interface{}{
+		{"path": "publishers/my-pub/books/1", "price": 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests, _ = requestBody["requests"].([]interface{})
+	if len(requests) != 1 || requests[0].(map[string]interface{})["path"] != "publishers/my-pub/books/1" {
+		t.Errorf("unexpected update requests %v", requestBody)
+	}
+	_, err = c.BatchUpdate(ctx, r, "http://localhost:8081", parameters, []map[string]interface{}{{"price": 3}})
+	if err == nil {
+		t.Errorf("expected an error for an update without a path")
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books:batchDelete", recordBody(""))
+	err = c.BatchDelete(ctx, r, "http://localhost:8081", parameters, []string{"publishers/my-pub/books/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths, _ := requestBody["paths"].([]interface{}); len(paths) != 1 {
+		t.Errorf("unexpected delete request %v", requestBody)
+	}
+}
+
+func TestBatchMethodsRequireDefinition(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.ZeroCallCounters()
+
+	r := api.ExampleAPI().Resources["book"]
+	c := NewClient(http.DefaultClient)
+	ctx := context.Background()
+	parameters := map[string]string{"publisher_id": "my-pub"}
+
+	if _, err := c.BatchGet(ctx, r, "http://localhost:8081", parameters, []string{"publishers/my-pub/books/1"}); err == nil {
+		t.Errorf("expected batch_get to be rejected for a resource without it")
+	}
+	if _, err := c.BatchCreate(ctx, r, "http://localhost:8081", parameters, []map[string]interface{}{{"id": "1"}}); err == nil {
+		t.Errorf("expected batch_create to be rejected for a resource without it")
+	}
+	if _, err := c.BatchUpdate(ctx, r, "http://localhost:8081", parameters, []map[string]interface{}{{"path": "publishers/my-pub/books/1"}}); err == nil {
+		t.Errorf("expected batch_update to be rejected for a resource without it")
+	}
+	if err := c.BatchDelete(ctx, r, "http://localhost:8081", parameters, []string{"publishers/my-pub/books/1"}); err == nil {
+		t.Errorf("expected batch_delete to be rejected for a resource without it")
+	}
+	if n := httpmock.GetTotalCallCount(); n != 0 {
+		t.Errorf("expected no requests to be sent, got %d", n)
+	}
+}
+
+func TestUndelete(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books/1:undelete",
+		httpmock.NewStringResponder(200, "{\"path\":\"publishers/my-pub/books/1\"}"))
+
+	c := NewClient(http.DefaultClient)
+	data, err := c.Undelete(context.Background(), "http://localhost:8081", "/publishers/my-pub/books/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["path"] != "publishers/my-pub/books/1" {
+		t.Errorf("expected path to be 'publishers/my-pub/books/1', got '%v'", data["path"])
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books/1/revisions",
+		httpmock.NewStringResponder(200, "{\"results\":[{\"revision_id\":\"a\"},{\"revision_id\":\"b\"}]}"))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books/1@a",
+		httpmock.NewStringResponder(200, "{\"revision_id\":\"a\"}"))
+
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	revisions, err := c.ListRevisions(ctx, "http://localhost:8081", "/publishers/my-pub/books/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+
+	revision, err := c.GetRevision(ctx, "http://localhost:8081", "/publishers/my-pub/books/1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision["revision_id"] != "a" {
+		t.Errorf("expected revision_id to be 'a', got '%v'", revision["revision_id"])
+	}
+}
+
+func TestUpdateIfMatch(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("PATCH", "http://localhost:8081/publishers/my-pub/books/1",
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("If-Match") != "v1" {
+				return httpmock.NewStringResponse(412, "{}"), nil
+			}
+			return httpmock.NewStringResponse(200, "{\"etag\":\"v2\"}"), nil
+		})
+
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	if err := c.UpdateIfMatch(ctx, "http://localhost:8081", "/publishers/my-pub/books/1", map[string]interface{}{}, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	err := c.UpdateIfMatch(ctx, "http://localhost:8081", "/publishers/my-pub/books/1", map[string]interface{}{}, "v0")
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestReadModifyWrite(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// the resource is modified concurrently between the first read and
+	// write, so the first write fails.
+	etags := []string{"v1", "v2"}
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books/1",
+		func(req *http.Request) (*http.Response, error) {
+			etag := etags[0]
+			if len(etags) > 1 {
+				etags = etags[1:]
+			}
+			return httpmock.NewStringResponse(200, fmt.Sprintf("{\"etag\":%q,\"price\":1}", etag)), nil
+		})
+	httpmock.RegisterResponder("PATCH", "http://localhost:8081/publishers/my-pub/books/1",
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("If-Match") != "v2" {
+				return httpmock.NewStringResponse(412, "{}"), nil
+			}
+			return httpmock.NewStringResponse(200, "{\"etag\":\"v3\",\"price\":2}"), nil
+		})
+
+	c := NewClient(http.DefaultClient)
+	updated, err := c.ReadModifyWrite(context.Background(), "http://localhost:8081", "/publishers/my-pub/books/1",
+		func(resource map[string]interface{}) error {
+			resource["price"] = 2
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated["etag"] != "v3" {
+		t.Errorf("expected etag to be 'v3', got '%v'", updated["etag"])
+	}
+	if calls := httpmock.GetCallCountInfo()["PATCH http://localhost:8081/publishers/my-pub/books/1"]; calls != 2 {
+		t.Errorf("expected 2 PATCH requests, got %d", calls)
+	}
+}
+
+func TestValidateOnly(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books?id=my-book&validate_only=true",
+		httpmock.NewStringResponder(200, "{\"path\":\"/publishers/my-pub/books/my-book\"}"))
+	httpmock.RegisterResponder("DELETE", "http://localhost:8081/publishers/my-pub/books/1?validate_only=true",
+		httpmock.NewStringResponder(200, ""))
+
+	a := api.ExampleAPI()
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	body := map[string]interface{}{
+		"id": "my-book",
+	}
+	parameters := map[string]string{
+		"publisher_id": "my-pub",
+	}
+	if _, err := c.Create(ctx, a.Resources["book"], "http://localhost:8081/", body, parameters, ValidateOnly()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(ctx, "http://localhost:8081", "/publishers/my-pub/books/1", ValidateOnly()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateGeneratesRequestId(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	requestIds := []string{}
+	httpmock.RegisterResponder("POST", "=~^http://localhost:8081/publishers/my-pub/books",
+		func(req *http.Request) (*http.Response, error) {
+			requestIds = append(requestIds, req.URL.Query().Get("request_id"))
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+
+	a := api.ExampleAPI()
+	r := a.Resources["book"]
+	r.Methods.Create.SupportsRequestId = true
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	body := map[string]interface{}{
+		"id": "my-book",
+	}
+	parameters := map[string]string{
+		"publisher_id": "my-pub",
+	}
+	for _, opts := range [][]CallOption{nil, nil, {RequestId("my-request")}} {
+		if _, err := c.Create(ctx, r, "http://localhost:8081/", body, parameters, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(requestIds) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requestIds))
+	}
+	if requestIds[0] == "" || requestIds[0] == requestIds[1] {
+		t.Errorf("expected a new request id for each call, got %v", requestIds)
+	}
+	if requestIds[2] != "my-request" {
+		t.Errorf("expected request id to be 'my-request', got '%v'", requestIds[2])
+	}
+}
+
+func TestReadMask(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books/1?read_mask=price%2Cedition",
+		httpmock.NewStringResponder(200, "{\"price\":1}"))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books?read_mask=price",
+		httpmock.NewStringResponder(200, "{\"results\":[{\"price\":1}]}"))
+
+	a := api.ExampleAPI()
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	if _, err := c.Get(ctx, "http://localhost:8081", "/publishers/my-pub/books/1", ReadMask("price", "edition")); err != nil {
+		t.Fatal(err)
+	}
+	results, err := c.List(ctx, a.Resources["book"], "http://localhost:8081", map[string]string{"publisher_id": "my-pub"}, ReadMask("price"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+}
+
+func TestListOrderBy(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books?order_by=price+desc%2C+published",
+		httpmock.NewStringResponder(200, "{\"results\":[{\"price\":2},{\"price\":1}]}"))
+
+	a := api.ExampleAPI()
+	r := a.Resources["book"]
+	r.Methods.List.SupportsOrderBy = true
+	r.Methods.List.OrderableFields = []string{"price", "published"}
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	parameters := map[string]string{"publisher_id": "my-pub"}
+
+	results, err := c.List(ctx, r, "http://localhost:8081", parameters, OrderBy("price desc, published"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+
+	for _, orderBy := range []string{"edition", "price descending", "price,"} {
+		if _, err := c.List(ctx, r, "http://localhost:8081", parameters, OrderBy(orderBy)); err == nil {
+			t.Errorf("expected order_by %q to be rejected", orderBy)
+		}
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", `http://localhost:8081/publishers/my-pub/books?filter=id+%3D%3D+%221%22`,
+		httpmock.NewStringResponder(200, "{\"results\":[{\"id\":\"1\"}]}"))
+
+	a := api.ExampleAPI()
+	if err := api.AddImplicitFieldsAndValidate(a); err != nil {
+		t.Fatal(err)
+	}
+	r := a.Resources["book"]
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	parameters := map[string]string{"publisher_id": "my-pub"}
+
+	r.Methods.List.SupportsFilter = false
+	if _, err := c.List(ctx, r, "http://localhost:8081", parameters, Filter(`id == "1"`)); err == nil {
+		t.Errorf("expected filter to be rejected by a list method without filter support")
+	}
+
+	r.Methods.List.SupportsFilter = true
+	results, err := c.List(ctx, r, "http://localhost:8081", parameters, Filter(`id == "1"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+
+	for _, f := range []string{`id ==`, `edition == 1`, `id == 1`} {
+		if _, err := c.List(ctx, r, "http://localhost:8081", parameters, Filter(f)); err == nil {
+			t.Errorf("expected filter %q to be rejected", f)
+		}
+	}
+}
+
+func TestListAll(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	booksUrl := "http://localhost:8081/publishers/my-pub/books"
+	httpmock.RegisterResponderWithQuery("GET", booksUrl, "max_page_size=2&skip=1",
+		httpmock.NewStringResponder(200, `{"results":[{"id":"2"},{"id":"3"}],"next_page_token":"a"}`))
+	httpmock.RegisterResponderWithQuery("GET", booksUrl, "max_page_size=2&page_token=a",
+		httpmock.NewStringResponder(200, `{"results":[{"id":"4"},{"id":"5"}],"unreachable":["publishers/other/books/6"],"next_page_token":"b"}`))
+	httpmock.RegisterResponderWithQuery("GET", booksUrl, "max_page_size=2&page_token=b",
+		httpmock.NewStringResponder(200, `{"results":[{"id":"7"}]}`))
+
+	a := api.ExampleAPI()
+	r := a.Resources["book"]
+	r.Methods.List.SupportsSkip = true
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	parameters := map[string]string{"publisher_id": "my-pub"}
+
+	ids := []string{}
+	var unreachable *UnreachableError
+	for book, err := range c.ListAll(ctx, r, "http://localhost:8081", parameters, MaxPageSize(2), Skip(1)) {
+		if err != nil {
+			if !errors.As(err, &unreachable) {
+				t.Fatal(err)
+			}
+			continue
+		}
+		ids = append(ids, book["id"].(string))
+	}
+	if strings.Join(ids, ",") != "2,3,4,5,7" {
+		t.Errorf("expected books 2,3,4,5,7, got %v", ids)
+	}
+	if unreachable == nil || len(unreachable.Paths) != 1 || unreachable.Paths[0] != "publishers/other/books/6" {
+		t.Errorf("expected book 6 to be unreachable, got %v", unreachable)
+	}
+
+	// stopping early does not fetch the remaining pages.
+	httpmock.ZeroCallCounters()
+	for range c.ListAll(ctx, r, "http://localhost:8081", parameters, MaxPageSize(2), Skip(1)) {
+		break
+	}
+	if n := httpmock.GetTotalCallCount(); n != 1 {
+		t.Errorf("expected 1 request after breaking out of the loop, got %d", n)
+	}
+
+	httpmock.ZeroCallCounters()
+	ids = []string{}
+	for book, err := range c.ListAll(ctx, r, "http://localhost:8081", parameters, MaxPageSize(2), Skip(1), MaxItems(3)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, book["id"].(string))
+	}
+	if strings.Join(ids, ",") != "2,3,4" {
+		t.Errorf("expected books 2,3,4, got %v", ids)
+	}
+	if n := httpmock.GetTotalCallCount(); n != 2 {
+		t.Errorf("expected 2 requests for 3 items, got %d", n)
+	}
+
+	r.Methods.List.SupportsSkip = false
+	for _, err := range c.ListAll(ctx, r, "http://localhost:8081", parameters, Skip(1)) {
+		if err == nil {
+			t.Errorf("expected skip to be rejected by a list method without skip support")
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// protojson omits done while the operation is running.
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers",
+		httpmock.NewStringResponder(200, `{"path":"operations/1"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/operations/1",
+		httpmock.ResponderFromMultipleResponses([]*http.Response{
+			httpmock.NewStringResponse(200, `{"path":"operations/1","metadata":{"progress":50}}`),
+			httpmock.NewStringResponse(200, `{"path":"operations/1","done":true,"response":{"path":"publishers/1"}}`),
+		}))
+	httpmock.RegisterResponder("DELETE", "http://localhost:8081/publishers/2",
+		httpmock.NewStringResponder(200, `{"path":"operations/2","done":true,"error":{"title":"permission denied"}}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/operations/3",
+		httpmock.NewStringResponder(200, `{"path":"operations/3"}`))
+
+	a := api.ExampleAPI()
+	r := a.Resources["publisher"]
+	r.Methods.Create.IsLongRunning = true
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	c.OperationBackoff = Backoff{Initial: time.Millisecond, Max: time.Millisecond}
+
+	// long-running methods wait for their operation by default.
+	publisher, err := c.Create(ctx, r, "http://localhost:8081", map[string]interface{}{}, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publisher["path"] != "publishers/1" {
+		t.Errorf("expected the created publisher, got %v", publisher)
+	}
+
+	// the responses of methods that are not long-running are not polled,
+	// even if they could be operations.
+	httpmock.ZeroCallCounters()
+	r.Methods.Create.IsLongRunning = false
+	m, err := c.Create(ctx, r, "http://localhost:8081", map[string]interface{}{}, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["path"] != "operations/1" || httpmock.GetTotalCallCount() != 1 {
+		t.Errorf("expected the response not to be polled, got %v", m)
+	}
+	r.Methods.Create.IsLongRunning = true
+
+	var opErr *OperationError
+	err = c.Delete(ctx, "http://localhost:8081", "publishers/2")
+	if !errors.As(err, &opErr) || opErr.Details["title"] != "permission denied" {
+		t.Errorf("expected the error of the operation, got %v", err)
+	}
+
+	// with NoWait, the operation is polled by the caller.
+	httpmock.ZeroCallCounters()
+	m, err = c.Create(ctx, r, "http://localhost:8081", map[string]interface{}{}, map[string]string{}, NoWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+	op, err := c.AsOperation("http://localhost:8081", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Done || op.Path != "operations/1" {
+		t.Errorf("expected operations/1 to be running, got %v", op)
+	}
+	if n := httpmock.GetTotalCallCount(); n != 1 {
+		t.Errorf("expected no polls with NoWait, got %d requests", n)
+	}
+
+	op, err = c.GetOperation(ctx, "http://localhost:8081", "operations/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := op.Result(); err == nil {
+		t.Errorf("expected the result of a running operation to be an error")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := op.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected waiting to stop at the deadline, got %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	problem := httpmock.NewStringResponse(404, `{"type":"https://example.com/not-found","title":"Not Found","detail":"publisher 1 does not exist","instance":"/publishers/1","resource":"publishers/1"}`)
+	problem.Header.Set("Content-Type", "application/problem+json")
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/1", httpmock.ResponderFromResponse(problem))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/2",
+		httpmock.NewStringResponder(404, ""))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/3",
+		httpmock.NewStringResponder(500, "<html>internal error</html>"))
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers",
+		httpmock.NewStringResponder(409, `{"error":{"title":"already exists"}}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/4",
+		httpmock.NewStringResponder(200, `{"error":"quota exceeded"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/5",
+		httpmock.NewStringResponder(200, `{"error":{"status":404,"detail":"publisher 5 was deleted"}}`))
+
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+
+	_, err := c.Get(ctx, "http://localhost:8081", "publishers/1")
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected an *Error, got %v", err)
+	}
+	if !IsNotFound(err) || IsConflict(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if e.Type != "https://example.com/not-found" || e.Detail != "publisher 1 does not exist" || e.Instance != "/publishers/1" {
+		t.Errorf("expected the problem details to be decoded, got %+v", e)
+	}
+	if e.Extensions["resource"] != "publishers/1" {
+		t.Errorf("expected the resource extension, got %v", e.Extensions)
+	}
+	if e.Method != "GET" || e.URL != "http://localhost:8081/publishers/1" {
+		t.Errorf("expected the request of the error, got %s %s", e.Method, e.URL)
+	}
+	if got := err.Error(); got != "GET http://localhost:8081/publishers/1: 404 Not Found: publisher 1 does not exist" {
+		t.Errorf("unexpected error message %q", got)
+	}
+
+	if _, err := c.Get(ctx, "http://localhost:8081", "publishers/2"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for an empty body, got %v", err)
+	}
+
+	_, err = c.Get(ctx, "http://localhost:8081", "publishers/3")
+	if !errors.As(err, &e) || e.StatusCode != 500 || string(e.Body) != "<html>internal error</html>" {
+		t.Errorf("expected an internal error with the raw body, got %v", err)
+	}
+
+	_, err = c.Create(ctx, api.ExampleAPI().Resources["publisher"], "http://localhost:8081", map[string]interface{}{}, map[string]string{})
+	if !IsConflict(err) || !errors.As(err, &e) || e.Title != "already exists" {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+
+	_, err = c.Get(ctx, "http://localhost:8081", "publishers/4")
+	if !errors.As(err, &e) || e.Detail != "quota exceeded" || e.StatusCode != 0 {
+		t.Errorf("expected the error of the body without a status code, got %v", err)
+	}
+
+	_, err = c.Get(ctx, "http://localhost:8081", "publishers/5")
+	if !IsNotFound(err) || !errors.As(err, &e) || e.Detail != "publisher 5 was deleted" {
+		t.Errorf("expected the status code of the error of the body, got %v", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	throttled := httpmock.NewStringResponse(429, "")
+	throttled.Header.Set("Retry-After", "0")
+	transient := func() httpmock.Responder {
+		return httpmock.ResponderFromMultipleResponses([]*http.Response{
+			httpmock.NewStringResponse(503, ""),
+			throttled,
+			httpmock.NewStringResponse(200, `{"path":"publishers/1"}`),
+		})
+	}
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/1", transient())
+	bodies := []string{}
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		return httpmock.NewStringResponse(503, ""), nil
+	})
+
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	c.RetryPolicy = DefaultRetryPolicy
+	c.RetryPolicy.Backoff = Backoff{Initial: time.Millisecond, Max: time.Millisecond}
+	retries := []string{}
+	c.RequestLoggingFunction = func(ctx context.Context, req *http.Request, args ...any) {
+		if len(args) > 0 {
+			retries = append(retries, fmt.Sprint(args...))
+		}
+	}
+
+	publisher, err := c.Get(ctx, "http://localhost:8081", "publishers/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publisher["path"] != "publishers/1" {
+		t.Errorf("expected the publisher after retries, got %v", publisher)
+	}
+	if len(retries) != 2 {
+		t.Errorf("expected 2 retries to be logged, got %v", retries)
+	}
+
+	// mutations are only retried with a request id.
+	r := api.ExampleAPI().Resources["publisher"]
+	body := map[string]interface{}{"description": "d"}
+	_, err = c.Create(ctx, r, "http://localhost:8081", body, map[string]string{})
+	if !errors.As(err, new(*Error)) || len(bodies) != 1 {
+		t.Errorf("expected a single attempt without a request id, got %d: %v", len(bodies), err)
+	}
+
+	bodies = []string{}
+	_, err = c.Create(ctx, r, "http://localhost:8081", body, map[string]string{}, RequestId("1"))
+	if err == nil || len(bodies) != DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("expected %d attempts with a request id, got %d: %v", DefaultRetryPolicy.MaxAttempts, len(bodies), err)
+	}
+	for _, b := range bodies {
+		if b != `{"description":"d"}` {
+			t.Errorf("expected the body to be sent with each attempt, got %q", b)
+		}
+	}
+
+	// without a retry policy, requests are sent once.
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/1", transient())
+	c.RetryPolicy = RetryPolicy{}
+	if _, err := c.Get(ctx, "http://localhost:8081", "publishers/1"); err == nil {
+		t.Errorf("expected the first error without a retry policy")
+	}
+}
+
+type contextKey string
+
+func TestInterceptors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers", func(req *http.Request) (*http.Response, error) {
+		if req.Context().Value(contextKey("span")) != "s1" {
+			t.Errorf("expected the request to be sent with the context of the interceptors")
+		}
+		if req.Header.Get("Authorization") != "Bearer token" {
+			return httpmock.NewStringResponse(401, ""), nil
+		}
+		resp := httpmock.NewStringResponse(200, `{"path":"publishers/1"}`)
+		resp.Header.Set("X-Trace-Id", "trace")
+		return resp, nil
+	})
+
+	r := api.ExampleAPI().Resources["publisher"]
+	ctx := context.WithValue(context.Background(), contextKey("tenant"), "t1")
+	c := NewClient(http.DefaultClient)
+	calls := []string{}
+	c.Use(
+		func(ctx context.Context, info *CallInfo, req *http.Request, next Invoker) (*http.Response, error) {
+			calls = append(calls, "auth")
+			if req.Context().Value(contextKey("tenant")) != "t1" {
+				t.Errorf("expected the request to carry the context of the call")
+			}
+			req.Header.Set("Authorization", "Bearer token")
+			return next(context.WithValue(ctx, contextKey("span"), "s1"), req)
+		},
+		func(ctx context.Context, info *CallInfo, req *http.Request, next Invoker) (*http.Response, error) {
+			calls = append(calls, fmt.Sprintf("metrics %s %s", info.Resource.Singular, info.Method))
+			resp, err := next(ctx, req)
+			if err == nil {
+				calls = append(calls, "trace "+resp.Header.Get("X-Trace-Id"))
+			}
+			return resp, err
+		},
+	)
+
+	publisher, err := c.Create(ctx, r, "http://localhost:8081", map[string]interface{}{}, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publisher["path"] != "publishers/1" {
+		t.Errorf("expected the created publisher, got %v", publisher)
+	}
+	if got := strings.Join(calls, ", "); got != "auth, metrics publisher Create, trace trace" {
+		t.Errorf("unexpected interceptor calls %q", got)
+	}
+
+	// the operations of a resource are polled with the resource.
+	httpmock.RegisterResponder("DELETE", "http://localhost:8081/publishers/my-pub/tomes/1",
+		httpmock.NewStringResponder(200, `{"path":"operations/1"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/operations/1",
+		httpmock.NewStringResponder(200, `{"path":"operations/1","done":true}`))
+	c.OperationBackoff = Backoff{Initial: time.Millisecond}
+	calls = []string{}
+	tomes := NewTypedResourceClient[map[string]interface{}](c, api.ExampleAPI().Resources["tome"], "http://localhost:8081")
+	if err := tomes.Delete(ctx, "publishers/my-pub/tomes/1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(calls, ", "); got != "auth, metrics tome Delete, trace , auth, metrics tome GetOperation, trace " {
+		t.Errorf("unexpected interceptor calls %q", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("PUT", "http://localhost:8081/publishers/my-pub/tomes/1", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if string(body) != `{"name":"n"}` {
+			t.Errorf("expected the full resource to be sent, got %s", body)
+		}
+		return httpmock.NewStringResponse(200, `{"path":"operations/1","done":true,"response":{"path":"publishers/my-pub/tomes/1","name":"n"}}`), nil
+	})
+
+	a := api.ExampleAPI()
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	tome, err := c.Apply(ctx, a.Resources["tome"], "http://localhost:8081", "publishers/my-pub/tomes/1", map[string]interface{}{"name": "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tome["name"] != "n" {
+		t.Errorf("expected the applied tome, got %v", tome)
+	}
+
+	if _, err := c.Apply(ctx, a.Resources["publisher"], "http://localhost:8081", "publishers/my-pub", map[string]interface{}{}); err == nil {
+		t.Errorf("expected apply to be rejected for a resource without an apply method")
+	}
+
+	// a generated request id makes apply retryable.
+	requestIds := []string{}
+	httpmock.RegisterResponder("PUT", "http://localhost:8081/publishers/my-pub/tomes/2", func(req *http.Request) (*http.Response, error) {
+		requestIds = append(requestIds, req.URL.Query().Get("request_id"))
+		if len(requestIds) == 1 {
+			return httpmock.NewStringResponse(503, ""), nil
+		}
+		return httpmock.NewStringResponse(200, `{"path":"operations/2","done":true,"response":{"name":"n"}}`), nil
+	})
+	c.RetryPolicy = RetryPolicy{MaxAttempts: 2, Backoff: Backoff{Initial: time.Millisecond}, RetryableStatusCodes: []int{503}}
+	a.Resources["tome"].Methods.Apply.SupportsRequestId = true
+	if _, err := c.Apply(ctx, a.Resources["tome"], "http://localhost:8081", "publishers/my-pub/tomes/2", map[string]interface{}{"name": "n"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requestIds) != 2 || requestIds[0] == "" || requestIds[0] != requestIds[1] {
+		t.Errorf("expected the request to be retried with a generated request id, got %v", requestIds)
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books/1:archive",
+		httpmock.NewStringResponder(200, `{"archived":true}`))
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/tomes/1:archive",
+		httpmock.NewStringResponder(200, `{"path":"operations/1","done":false}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/operations/1",
+		httpmock.NewStringResponder(200, `{"path":"operations/1","done":true,"response":{"archived":true}}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books/1:preview",
+		httpmock.NewStringResponder(200, `{"preview":"..."}`))
+	moved := []string{}
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books/1:move", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		moved = append(moved, string(body))
+		return httpmock.NewStringResponse(200, `{}`), nil
+	})
+
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.CustomMethods = append(book.CustomMethods,
+		&api.CustomMethod{Name: "preview", Method: "GET"},
+		&api.CustomMethod{Name: "move", Method: "POST", Request: &openapi.Schema{
+			Type:     "object",
+			Required: []string{"destination"},
+			Properties: map[string]openapi.Schema{
+				"destination": {Type: "string"},
+			},
+		}},
+	)
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	c.OperationBackoff = Backoff{Initial: time.Millisecond}
+
+	data, err := c.Invoke(ctx, book, "http://localhost:8081", "archive", "publishers/my-pub/books/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["archived"] != true {
+		t.Errorf("expected the book to be archived, got %v", data)
+	}
+
+	data, err = c.Invoke(ctx, a.Resources["tome"], "http://localhost:8081", "archive", "publishers/my-pub/tomes/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["archived"] != true {
+		t.Errorf("expected the response of the operation, got %v", data)
+	}
+
+	data, err = c.Invoke(ctx, book, "http://localhost:8081", "preview", "publishers/my-pub/books/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["preview"] != "..." {
+		t.Errorf("expected the preview, got %v", data)
+	}
+
+	// bodies are sent as they are unless requests are validated.
+	if _, err := c.Invoke(ctx, book, "http://localhost:8081", "move", "publishers/my-pub/books/1", map[string]interface{}{"destination": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 1 || moved[0] != `{"destination":1}` {
+		t.Errorf("expected the invalid body to be sent, got %v", moved)
+	}
+
+	c.RequestValidation = ValidateRejectReadOnly
+	for _, body := range []map[string]interface{}{
+		{},
+		{"destination": 1},
+		{"destination": "publishers/other", "copy": true},
+	} {
+		var verr *ValidationError
+		if _, err := c.Invoke(ctx, book, "http://localhost:8081", "move", "publishers/my-pub/books/1", body); !errors.As(err, &verr) {
+			t.Errorf("expected body %v to be rejected, got %v", body, err)
+		}
+	}
+	if len(moved) != 1 {
+		t.Errorf("expected invalid bodies not to be sent, got %v", moved)
+	}
+
+	if _, err := c.Invoke(ctx, book, "http://localhost:8081", "missing", "publishers/my-pub/books/1", nil); err == nil {
+		t.Errorf("expected an error for an unknown custom method")
+	}
+}
+
+type testBook struct {
+	Path string `json:"path,omitempty"`
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func TestTypedResourceClient(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers/my-pub/books", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if string(body) != `{"id":"1","name":"n"}` {
+			t.Errorf("expected the book to be marshalled, got %s", body)
+		}
+		return httpmock.NewStringResponse(200, `{"path":"publishers/my-pub/books/1","name":"n"}`), nil
+	})
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books/1",
+		httpmock.NewStringResponder(200, `{"path":"publishers/my-pub/books/1","name":"n"}`))
+	httpmock.RegisterResponder("PATCH", "http://localhost:8081/publishers/my-pub/books/1",
+		httpmock.NewStringResponder(200, `{"path":"publishers/my-pub/books/1","name":"m"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/my-pub/books",
+		httpmock.NewStringResponder(200, `{"results":[{"path":"publishers/my-pub/books/1"},{"path":"publishers/my-pub/books/2"}]}`))
+	httpmock.RegisterResponder("DELETE", "http://localhost:8081/publishers/my-pub/books/1",
+		httpmock.NewStringResponder(200, ""))
+
+	a := api.ExampleAPI()
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+	resources := []string{}
+	c.Use(func(ctx context.Context, info *CallInfo, req *http.Request, next Invoker) (*http.Response, error) {
+		if info.Resource == nil {
+			t.Errorf("expected the resource of %s", info.Method)
+		} else {
+			resources = append(resources, info.Method+" "+info.Resource.Singular)
+		}
+		return next(ctx, req)
+	})
+	books := NewTypedResourceClient[testBook](c, a.Resources["book"], "http://localhost:8081")
+	parameters := map[string]string{"publisher_id": "my-pub"}
+
+	path, err := books.Path(parameters, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "publishers/my-pub/books/1" {
+		t.Errorf("expected the path of book 1, got %s", path)
+	}
+
+	book, err := books.Create(ctx, parameters, testBook{Id: "1", Name: "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Path != path || book.Name != "n" {
+		t.Errorf("expected the created book, got %+v", book)
+	}
+
+	book, err = books.Get(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Name != "n" {
+		t.Errorf("expected the book, got %+v", book)
+	}
+
+	book, err = books.Update(ctx, path, testBook{Name: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Name != "m" {
+		t.Errorf("expected the updated book, got %+v", book)
+	}
+
+	list, err := books.List(ctx, parameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for b, err := range books.All(ctx, parameters) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, b.Path)
+	}
+	if len(list) != 2 || len(paths) != 2 || paths[1] != "publishers/my-pub/books/2" {
+		t.Errorf("expected 2 books, got %+v and %v", list, paths)
+	}
+
+	if err := books.Delete(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	if resources[len(resources)-1] != "Delete book" {
+		t.Errorf("expected the resource of the delete call, got %v", resources)
+	}
+
+	if _, err := books.Apply(ctx, path, testBook{Name: "m"}); err == nil || !strings.Contains(err.Error(), "apply") {
+		t.Errorf("expected apply to be rejected for a resource without an apply method, got %v", err)
+	}
+	if n := httpmock.GetCallCountInfo()["PUT http://localhost:8081/publishers/my-pub/books/1"]; n != 0 {
+		t.Errorf("expected no apply request, got %d", n)
+	}
+
+	publishers := NewTypedResourceClient[map[string]interface{}](c, a.Resources["publisher"], "http://localhost:8081")
+	if err := publishers.Delete(ctx, "publishers/my-pub"); err == nil {
+		t.Errorf("expected delete to be rejected for a resource without a delete method")
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	sent := []string{}
+	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		sent = append(sent, string(body))
+		return httpmock.NewStringResponse(200, `{"path":"publishers/1"}`), nil
+	})
+
+	r := api.ExampleAPI().Resources["publisher"]
+	r.Schema = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"title", "status"},
+		Properties: map[string]openapi.Schema{
+			"path":   {Type: "string", ReadOnly: true},
+			"title":  {Type: "string"},
+			"status": {Type: "string", Enum: []interface{}{"ACTIVE", "CLOSED"}},
+			"address": {Type: "object", Properties: map[string]openapi.Schema{
+				"zip": {Type: "integer"},
+			}},
+			"tags":   {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			"labels": {Type: "object"},
+		},
+	}
+	ctx := context.Background()
+	c := NewClient(http.DefaultClient)
+
+	invalid := map[string]interface{}{
+		"path":    "publishers/1",
+		"status":  "OPEN",
+		"address": map[string]interface{}{"zip": 1.5},
+		"owner":   "me",
+	}
+	c.RequestValidation = ValidateRejectReadOnly
+	_, err := c.Create(ctx, r, "http://localhost:8081", invalid, map[string]string{})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	fields := []string{}
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	if got := strings.Join(fields, ","); got != "title,address.zip,owner,path,status" {
+		t.Errorf("expected errors for each invalid field, got %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("expected invalid requests not to be sent, got %v", sent)
+	}
+
+	c.RequestValidation = ValidateStripReadOnly
+	valid := map[string]interface{}{"path": "publishers/1", "title": "t", "status": "ACTIVE"}
+	if _, err := c.Create(ctx, r, "http://localhost:8081", valid, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != `{"status":"ACTIVE","title":"t"}` {
+		t.Errorf("expected the read-only path to be stripped, got %v", sent)
+	}
+	if valid["path"] != "publishers/1" {
+		t.Errorf("expected the body of the caller to be left unchanged, got %v", valid)
+	}
+
+	// bodies are validated as the JSON they are sent as.
+	typed := map[string]interface{}{
+		"title":   "t",
+		"status":  "ACTIVE",
+		"tags":    []string{"a", "b"},
+		"labels":  map[string]string{"k": "v"},
+		"address": map[string]interface{}{"zip": uint(12345)},
+	}
+	if _, err := c.Create(ctx, r, "http://localhost:8081", typed, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 2 || sent[1] != `{"address":{"zip":12345},"labels":{"k":"v"},"status":"ACTIVE","tags":["a","b"],"title":"t"}` {
+		t.Errorf("expected the body to be sent unchanged, got %v", sent)
+	}
+
+	c.RequestValidation = ValidateNone
+	if _, err := c.Create(ctx, r, "http://localhost:8081", invalid, map[string]string{}); err != nil {
+		t.Errorf("expected bodies not to be validated by default, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrPreconditionFailed is returned when the etag sent with a
+// conditional request does not match the etag of the resource, because
+// the resource was modified since it was read (aep.dev/154).
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// maxErrorBodyLength bounds the length of the body shown by Error.
+const maxErrorBodyLength = 200
+
+// problemFields are the members of a problem details object that are
+// not extensions (RFC 9457).
+var problemFields = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// Error is an error returned by a service (aep.dev/193). The problem
+// details of the response (RFC 9457) are decoded if it has any.
+//
+// Use errors.As to inspect it, or helpers such as IsNotFound.
+type Error struct {
+	// StatusCode is the HTTP status code of the response, or the status
+	// of the error in the body of a successful response. It is 0 if that
+	// error has none.
+	StatusCode int
+	Type       string
+	Title      string
+	Detail     string
+	Instance   string
+	// Extensions are the other members of the problem details.
+	Extensions map[string]interface{}
+	// Body is the raw body of the response.
+	Body []byte
+	// Method and URL are those of the request.
+	Method string
+	URL    string
+}
+
+func (e *Error) Error() string {
+	parts := []string{}
+	if e.Method != "" {
+		parts = append(parts, e.Method+" "+e.URL)
+	}
+	if e.StatusCode != 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)))
+	}
+	details := []string{}
+	for _, s := range []string{e.Title, e.Detail} {
+		if s != "" && s != http.StatusText(e.StatusCode) {
+			details = append(details, s)
+		}
+	}
+	// bodies that are not problem details, e.g. HTML error pages, are
+	// shown truncated.
+	if len(details) == 0 && len(e.Body) > 0 {
+		body := strings.TrimSpace(string(e.Body))
+		if len(body) > maxErrorBodyLength {
+			body = body[:maxErrorBodyLength] + "..."
+		}
+		details = append(details, body)
+	}
+	parts = append(parts, details...)
+	if len(parts) == 0 {
+		return "unknown error"
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Is makes errors with a 412 status code match ErrPreconditionFailed.
+func (e *Error) Is(target error) bool {
+	return target == ErrPreconditionFailed && e.StatusCode == http.StatusPreconditionFailed
+}
+
+// newError returns the error of a response with an error status code,
+// or of a successful response whose body is an error. The status code of
+// the latter is that of the error, if it has one.
+func newError(resp *http.Response, body []byte) *Error {
+	e := &Error{Body: body}
+	if resp.StatusCode >= http.StatusBadRequest {
+		e.StatusCode = resp.StatusCode
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+	var problem map[string]interface{}
+	if json.Unmarshal(body, &problem) == nil {
+		e.setProblem(problem)
+	}
+	return e
+}
+
+// setProblem decodes the problem details of e. The problem may also be
+// wrapped in an "error" member.
+func (e *Error) setProblem(problem map[string]interface{}) {
+	switch wrapped := problem["error"].(type) {
+	case map[string]interface{}:
+		problem = wrapped
+	case string:
+		e.Detail = wrapped
+		return
+	}
+	if status, ok := problem["status"].(float64); ok && e.StatusCode == 0 {
+		e.StatusCode = int(status)
+	}
+	e.Type, _ = problem["type"].(string)
+	e.Title, _ = problem["title"].(string)
+	e.Detail, _ = problem["detail"].(string)
+	e.Instance, _ = problem["instance"].(string)
+	for key, value := range problem {
+		if problemFields[key] {
+			continue
+		}
+		if e.Extensions == nil {
+			e.Extensions = map[string]interface{}{}
+		}
+		e.Extensions[key] = value
+	}
+}
+
+// IsNotFound returns true if err is an *Error for a resource that does
+// not exist.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if err is an *Error for a request that
+// conflicts with the state of the resource, e.g. because it already
+// exists.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsPermissionDenied returns true if err is an *Error for a request
+// that the caller is not allowed to make.
+func IsPermissionDenied(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+func hasStatusCode(err error, code int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == code
+}
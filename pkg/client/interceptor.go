@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+)
+
+// CallInfo describes the call of the client that sends a request.
+type CallInfo struct {
+	// Resource is the resource of the call. It is nil for methods of
+	// Client that are called with just the path of a resource, e.g. Get
+	// or Delete, and for the operations they return. TypedResourceClient
+	// always sets it.
+	Resource *api.Resource
+	// Method is the logical method of the call, e.g. "Create",
+	// "BatchGet" or "GetOperation", or the name of a custom method.
+	Method string
+}
+
+// Invoker sends a request, and returns its response.
+type Invoker func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// Interceptor intercepts the requests of the client, e.g. to add
+// credentials, trace or sign them. It may modify the request before
+// calling next to send it, and inspect or replace the response after.
+// Interceptors are called for each attempt of a retried request.
+type Interceptor func(ctx context.Context, info *CallInfo, req *http.Request, next Invoker) (*http.Response, error)
+
+// Use appends interceptors to the interceptors of the client. The first
+// interceptor is the outermost one: it sees the request first, and the
+// response last.
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.Interceptors = append(c.Interceptors, interceptors...)
+}
+
+// invoker returns the invoker that sends the requests of a call through
+// the interceptors of the client.
+func (c *Client) invoker(info *CallInfo) Invoker {
+	invoke := func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		return c.client.Do(req.WithContext(ctx))
+	}
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.Interceptors[i], invoke
+		invoke = func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			return interceptor(ctx, info, req, next)
+		}
+	}
+	return invoke
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+)
+
+// operationFields are the fields of an aep.api.Operation
+// (aep.dev/151).
+var operationFields = map[string]bool{
+	"path":     true,
+	"done":     true,
+	"metadata": true,
+	"error":    true,
+	"response": true,
+}
+
+// Backoff is an exponential backoff between attempts: the first delay
+// is Initial, and each following one is Multiplier times longer, up to
+// Max. Zero fields use the defaults of DefaultBackoff.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// DefaultBackoff is the backoff used by NewClient to poll operations.
+var DefaultBackoff = Backoff{
+	Initial:    time.Second,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+}
+
+// delay returns the delay before attempt (starting from 1).
+func (b Backoff) delay(attempt int) time.Duration {
+	if b.Initial <= 0 {
+		b.Initial = DefaultBackoff.Initial
+	}
+	if b.Max <= 0 {
+		b.Max = DefaultBackoff.Max
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = DefaultBackoff.Multiplier
+	}
+	d := float64(b.Initial)
+	for i := 1; i < attempt && d < float64(b.Max); i++ {
+		d *= b.Multiplier
+	}
+	return min(time.Duration(d), b.Max)
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Operation is a handle on a long-running operation (aep.dev/151).
+type Operation struct {
+	Path     string
+	Done     bool
+	Metadata map[string]interface{}
+	// Response is the result of the operation once it is done, e.g. the
+	// created resource.
+	Response map[string]interface{}
+	// Error is set if the operation failed.
+	Error map[string]interface{}
+
+	client    *Client
+	serverUrl string
+	// resource is the resource of the method that returned the
+	// operation, if it is known.
+	resource *api.Resource
+}
+
+// OperationError is returned when a long-running operation fails.
+type OperationError struct {
+	Path string
+	// Details is the error of the operation.
+	Details map[string]interface{}
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %s failed: %v", e.Path, e.Details)
+}
+
+// Unwrap returns the problem details of the operation error as an
+// *Error, so that helpers such as IsNotFound apply to it.
+func (e *OperationError) Unwrap() error {
+	err := &Error{}
+	err.setProblem(e.Details)
+	return err
+}
+
+// isOperation returns true if m is an operation, rather than a
+// resource. A missing done field is false, as protojson omits it.
+func isOperation(m map[string]interface{}) bool {
+	if done, ok := m["done"]; ok {
+		if _, ok := done.(bool); !ok {
+			return false
+		}
+	}
+	if _, ok := m["path"].(string); !ok {
+		return false
+	}
+	for key := range m {
+		if !operationFields[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// looksLikeOperation returns true if m is the operation of a method
+// that is not known to be long-running or not. Only operations with a
+// done field are recognized, since a resource may have just a path.
+func looksLikeOperation(m map[string]interface{}) bool {
+	_, ok := m["done"]
+	return ok && isOperation(m)
+}
+
+// AsOperation returns a handle on the operation m, as returned by a
+// long-running method called with NoWait.
+func (c *Client) AsOperation(serverUrl string, m map[string]interface{}) (*Operation, error) {
+	if !isOperation(m) {
+		return nil, fmt.Errorf("%v is not an operation", m)
+	}
+	op := &Operation{client: c, serverUrl: serverUrl}
+	op.update(m)
+	return op, nil
+}
+
+// GetOperation returns a handle on the operation at path.
+func (c *Client) GetOperation(ctx context.Context, serverUrl string, path string) (*Operation, error) {
+	op := &Operation{Path: path, client: c, serverUrl: serverUrl}
+	if err := op.Poll(ctx); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (op *Operation) update(m map[string]interface{}) {
+	op.Path = strings.TrimPrefix(m["path"].(string), "/")
+	op.Done, _ = m["done"].(bool)
+	op.Metadata, _ = m["metadata"].(map[string]interface{})
+	op.Response, _ = m["response"].(map[string]interface{})
+	op.Error, _ = m["error"].(map[string]interface{})
+}
+
+// Poll refreshes the state of the operation once, without waiting for
+// it to be done.
+func (op *Operation) Poll(ctx context.Context) error {
+	m, err := op.client.get(ctx, &CallInfo{Resource: op.resource, Method: "GetOperation"}, op.serverUrl, op.Path)
+	if err != nil {
+		return fmt.Errorf("error polling operation %s: %w", op.Path, err)
+	}
+	if !isOperation(m) {
+		return fmt.Errorf("%s is not an operation: %v", op.Path, m)
+	}
+	op.update(m)
+	return nil
+}
+
+// Result returns the response of a done operation, or an
+// *OperationError if it failed.
+func (op *Operation) Result() (map[string]interface{}, error) {
+	if !op.Done {
+		return nil, fmt.Errorf("operation %s is not done", op.Path)
+	}
+	if op.Error != nil {
+		return nil, &OperationError{Path: op.Path, Details: op.Error}
+	}
+	if op.Response == nil {
+		return map[string]interface{}{}, nil
+	}
+	return op.Response, nil
+}
+
+// Wait polls the operation with the OperationBackoff of the client until
+// it is done, and returns its result. Set a deadline on ctx to bound the
+// wait.
+func (op *Operation) Wait(ctx context.Context) (map[string]interface{}, error) {
+	for attempt := 1; !op.Done; attempt++ {
+		if err := sleep(ctx, op.client.OperationBackoff.delay(attempt)); err != nil {
+			return nil, fmt.Errorf("error waiting for operation %s: %w", op.Path, err)
+		}
+		if err := op.Poll(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return op.Result()
+}
+
+// wait waits for the operation m of a long-running method of r (nil
+// if unknown) to be done, unless the call was made with NoWait, and
+// returns its result. Other responses are returned as is.
+func (c *Client) wait(ctx context.Context, r *api.Resource, serverUrl string, m map[string]interface{}, o *callOptions, longRunning bool) (map[string]interface{}, error) {
+	if o.noWait || !longRunning {
+		return m, nil
+	}
+	op, err := c.AsOperation(serverUrl, m)
+	if err != nil {
+		return nil, err
+	}
+	op.resource = r
+	return op.Wait(ctx)
+}
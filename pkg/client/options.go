@@ -0,0 +1,222 @@
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/aep-dev/aep-lib-go/pkg/constants"
+	"github.com/aep-dev/aep-lib-go/pkg/filter"
+)
+
+// CallOption configures a single call of the client.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	validateOnly bool
+	requestId    string
+	readMask     []string
+	orderBy      string
+	filter       string
+	maxPageSize  int
+	skip         int
+	maxItems     int
+	noWait       bool
+}
+
+// ValidateOnly asks the service to validate the request without applying
+// it (aep.dev/163). The method must support validate_only.
+func ValidateOnly() CallOption {
+	return func(o *callOptions) {
+		o.validateOnly = true
+	}
+}
+
+// RequestId sends id as the request_id of a mutation (aep.dev/155), so
+// that the service applies it only once, however often it is retried.
+// Reuse the same id to retry the same logical mutation.
+//
+// Create and Apply generate a request id for methods that support
+// request_id.
+// Update and Delete only send one if this option is passed, e.g. with
+// RequestId(NewRequestId()).
+func RequestId(id string) CallOption {
+	return func(o *callOptions) {
+		o.requestId = id
+	}
+}
+
+// NewRequestId returns a new random request id (a version 4 UUID).
+func NewRequestId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// only possible if the entropy source of the system is broken.
+		panic(fmt.Sprintf("error generating request id: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// ReadMask asks the service to return only the given fields of the
+// resources (aep.dev/157), e.g. ReadMask("title", "author.name"). The
+// method must support read_mask.
+func ReadMask(fields ...string) CallOption {
+	return func(o *callOptions) {
+		o.readMask = append(o.readMask, fields...)
+	}
+}
+
+// OrderBy orders the results of a List call (aep.dev/132), e.g.
+// OrderBy("create_time desc, title"). The fields are validated against
+// the orderable fields of the resource.
+func OrderBy(orderBy string) CallOption {
+	return func(o *callOptions) {
+		o.orderBy = orderBy
+	}
+}
+
+// Filter only lists the resources that match a filter (aep.dev/160),
+// e.g. Filter(`title.startsWith("The") && price < 20`). The filter is
+// checked against the schema of the resource before it is sent.
+func Filter(expr string) CallOption {
+	return func(o *callOptions) {
+		o.filter = expr
+	}
+}
+
+// MaxPageSize sets the maximum number of resources of each page of a
+// List call (aep.dev/158). The service may return fewer.
+func MaxPageSize(n int) CallOption {
+	return func(o *callOptions) {
+		o.maxPageSize = n
+	}
+}
+
+// Skip skips the first n resources of a List call (aep.dev/158). The
+// method must support skip.
+func Skip(n int) CallOption {
+	return func(o *callOptions) {
+		o.skip = n
+	}
+}
+
+// MaxItems bounds the total number of resources iterated over by
+// ListAll, however many pages it takes.
+func MaxItems(n int) CallOption {
+	return func(o *callOptions) {
+		o.maxItems = n
+	}
+}
+
+// NoWait returns the operation of a long-running method as soon as it
+// is started (aep.dev/151), instead of waiting for it to be done and
+// returning its result. Use Client.AsOperation to poll it.
+func NoWait() CallOption {
+	return func(o *callOptions) {
+		o.noWait = true
+	}
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// query returns the query parameters that the options add to a request.
+func (o *callOptions) query() url.Values {
+	query := url.Values{}
+	if o.validateOnly {
+		query.Set(constants.FIELD_VALIDATE_ONLY_NAME, "true")
+	}
+	if o.requestId != "" {
+		query.Set(constants.FIELD_REQUEST_ID_NAME, o.requestId)
+	}
+	if len(o.readMask) > 0 {
+		query.Set(constants.FIELD_READ_MASK_NAME, strings.Join(o.readMask, ","))
+	}
+	if o.orderBy != "" {
+		query.Set(constants.FIELD_ORDER_BY_NAME, o.orderBy)
+	}
+	if o.filter != "" {
+		query.Set(constants.FIELD_FILTER_NAME, o.filter)
+	}
+	if o.maxPageSize > 0 {
+		query.Set(constants.FIELD_MAX_PAGE_SIZE_NAME, strconv.Itoa(o.maxPageSize))
+	}
+	if o.skip > 0 {
+		query.Set(constants.FIELD_SKIP_NAME, strconv.Itoa(o.skip))
+	}
+	return query
+}
+
+// validateList returns an error if the options are not supported by the
+// List method of r.
+func (o *callOptions) validateList(r *api.Resource) error {
+	if err := o.validateOrderBy(r); err != nil {
+		return err
+	}
+	if err := o.validateFilter(r); err != nil {
+		return err
+	}
+	if o.skip > 0 && (r.Methods.List == nil || !r.Methods.List.SupportsSkip) {
+		return fmt.Errorf("resource %s does not support skip", r.Singular)
+	}
+	return nil
+}
+
+// validateOrderBy returns an error if the order_by of the options is
+// not supported by the List method of r.
+func (o *callOptions) validateOrderBy(r *api.Resource) error {
+	if o.orderBy == "" {
+		return nil
+	}
+	if r.Methods.List == nil || !r.Methods.List.SupportsOrderBy {
+		return fmt.Errorf("resource %s does not support order_by", r.Singular)
+	}
+	for _, clause := range strings.Split(o.orderBy, ",") {
+		words := strings.Fields(clause)
+		if len(words) == 0 || len(words) > 2 || (len(words) == 2 && words[1] != "asc" && words[1] != "desc") {
+			return fmt.Errorf("invalid order_by clause %q", strings.TrimSpace(clause))
+		}
+		orderable := r.Methods.List.OrderableFields
+		if len(orderable) > 0 && !slices.Contains(orderable, words[0]) {
+			return fmt.Errorf("resource %s can not be ordered by %q, only by %v", r.Singular, words[0], strings.Join(orderable, ", "))
+		}
+	}
+	return nil
+}
+
+// validateFilter returns an error if the filter of the options is not
+// supported by the List method of r, or is not valid for its schema.
+func (o *callOptions) validateFilter(r *api.Resource) error {
+	if o.filter == "" {
+		return nil
+	}
+	if r.Methods.List == nil || !r.Methods.List.SupportsFilter {
+		return fmt.Errorf("resource %s does not support filter", r.Singular)
+	}
+	e, err := filter.Parse(o.filter)
+	if err != nil {
+		return err
+	}
+	return filter.Check(e, r.Schema)
+}
+
+// withQuery appends query to u, which may already have a query.
+func withQuery(u string, query url.Values) string {
+	if len(query) == 0 {
+		return u
+	}
+	if strings.Contains(u, "?") {
+		return u + "&" + query.Encode()
+	}
+	return u + "?" + query.Encode()
+}
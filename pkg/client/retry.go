@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aep-dev/aep-lib-go/pkg/constants"
+)
+
+// RetryPolicy configures how the client retries requests that failed
+// transiently, e.g. because the service was overloaded.
+//
+// Only idempotent requests are retried (GET, PUT and DELETE), and
+// mutations sent with a request id (aep.dev/155), which the service
+// applies only once.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request,
+	// including the first one. Requests are not retried if it is less
+	// than 2.
+	MaxAttempts int
+	// Backoff is the delay between attempts, which is jittered. A
+	// Retry-After header in the response takes precedence.
+	Backoff Backoff
+	// RetryableStatusCodes are the status codes of the responses to
+	// retry. Requests that fail without a response are always retried.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy retries requests up to 3 times, for the status
+// codes of overloaded or unavailable services. Clients do not retry
+// requests unless their RetryPolicy is set, e.g. to DefaultRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	Backoff: Backoff{
+		Initial:    500 * time.Millisecond,
+		Max:        10 * time.Second,
+		Multiplier: 2,
+	},
+	RetryableStatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// idempotentMethods are the methods that can be retried safely.
+var idempotentMethods = []string{"GET", "HEAD", "OPTIONS", "PUT", "DELETE"}
+
+// canRetry returns true if sending req more than once has the same
+// effect as sending it once.
+func canRetry(req *http.Request) bool {
+	return slices.Contains(idempotentMethods, req.Method) || req.URL.Query().Has(constants.FIELD_REQUEST_ID_NAME)
+}
+
+// retryReason returns why the outcome of an attempt should be retried,
+// or an empty string if it should not be.
+func (p RetryPolicy) retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if slices.Contains(p.RetryableStatusCodes, resp.StatusCode) {
+		return resp.Status
+	}
+	return ""
+}
+
+// delay returns the delay before attempt (starting from 2), given the
+// response to the previous attempt.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+	// half of the delay is random, so that clients that failed together
+	// do not retry together.
+	d := p.Backoff.delay(attempt - 1)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses a Retry-After header, which is either a number of
+// seconds or a date.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// do sends the request of a call through the interceptors of the
+// client, retrying it according to its RetryPolicy. Retries are
+// reported to the RequestLoggingFunction, with the attempt, the delay
+// before it and the reason for it.
+func (c *Client) do(ctx context.Context, info *CallInfo, req *http.Request) (*http.Response, error) {
+	p := c.RetryPolicy
+	invoke := c.invoker(info)
+	for attempt := 1; ; attempt++ {
+		resp, err := invoke(ctx, req)
+		if attempt >= p.MaxAttempts || !canRetry(req) || ctx.Err() != nil {
+			return resp, err
+		}
+		reason := p.retryReason(resp, err)
+		if reason == "" {
+			return resp, err
+		}
+		delay := p.delay(attempt+1, resp)
+		if resp != nil {
+			// the connection is reused once the body is read.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("error retrying %s %s: %w", req.Method, req.URL, err)
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+		c.RequestLoggingFunction(ctx, req, "attempt", attempt+1, "delay", delay, "reason", reason)
+	}
+}
+
+// rewind returns a copy of req to send again, with its body reset.
+func rewind(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("error rewinding the body of %s %s: %v", req.Method, req.URL, err)
+		}
+		retry.Body = body
+	}
+	return retry, nil
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+)
+
+// TypedResourceClient calls the standard methods of a resource with
+// resources of type T, a struct with JSON tags matching the schema of
+// the resource, e.g.
+//
+//	type Book struct {
+//		Path  string `json:"path,omitempty"`
+//		Title string `json:"title,omitempty"`
+//	}
+//
+//	books := NewTypedResourceClient[Book](c, a.Resources["book"], serverUrl)
+//	book, err := books.Create(ctx, map[string]string{"publisher_id": "p"}, Book{Title: "t"})
+//
+// Methods that the resource does not support return an error, without
+// sending a request.
+type TypedResourceClient[T any] struct {
+	Client    *Client
+	Resource  *api.Resource
+	ServerUrl string
+}
+
+// NewTypedResourceClient binds a client to the resource r of the API
+// served at serverUrl.
+func NewTypedResourceClient[T any](c *Client, r *api.Resource, serverUrl string) *TypedResourceClient[T] {
+	return &TypedResourceClient[T]{Client: c, Resource: r, ServerUrl: serverUrl}
+}
+
+// Path returns the path of the resource with the given id, under the
+// parents identified by parameters (e.g. "publisher_id"). The id of a
+// singleton is ignored.
+func (tc *TypedResourceClient[T]) Path(parameters map[string]string, id string) (string, error) {
+	collection, err := basePath(context.TODO(), tc.Resource, "", parameters, "")
+	if err != nil {
+		return "", err
+	}
+	if tc.Resource.Singleton {
+		elems := tc.Resource.PatternElems()
+		id = elems[len(elems)-1]
+	}
+	return strings.TrimPrefix(collection, "/") + "/" + id, nil
+}
+
+func (tc *TypedResourceClient[T]) Create(ctx context.Context, parameters map[string]string, resource T, opts ...CallOption) (T, error) {
+	if err := tc.supports("create", tc.Resource.Methods.Create != nil); err != nil {
+		return zero[T](), err
+	}
+	body, err := toMap(resource)
+	if err != nil {
+		return zero[T](), err
+	}
+	return fromMap[T](tc.Client.Create(ctx, tc.Resource, tc.ServerUrl, body, parameters, opts...))
+}
+
+func (tc *TypedResourceClient[T]) Get(ctx context.Context, path string, opts ...CallOption) (T, error) {
+	if err := tc.supports("get", tc.Resource.Methods.Get != nil); err != nil {
+		return zero[T](), err
+	}
+	return fromMap[T](tc.Client.get(ctx, &CallInfo{Resource: tc.Resource, Method: "Get"}, tc.ServerUrl, path, opts...))
+}
+
+// List returns the first page of the resources under the parents
+// identified by parameters. Use All to iterate over all of them.
+func (tc *TypedResourceClient[T]) List(ctx context.Context, parameters map[string]string, opts ...CallOption) ([]T, error) {
+	if err := tc.supports("list", tc.Resource.Methods.List != nil); err != nil {
+		return nil, err
+	}
+	ms, err := tc.Client.List(ctx, tc.Resource, tc.ServerUrl, parameters, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]T, 0, len(ms))
+	for _, m := range ms {
+		resource, err := fromMap[T](m, nil)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// All iterates over all the resources under the parents identified by
+// parameters, as Client.ListAll does.
+func (tc *TypedResourceClient[T]) All(ctx context.Context, parameters map[string]string, opts ...CallOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err := tc.supports("list", tc.Resource.Methods.List != nil); err != nil {
+			yield(zero[T](), err)
+			return
+		}
+		for m, err := range tc.Client.ListAll(ctx, tc.Resource, tc.ServerUrl, parameters, opts...) {
+			if !yield(fromMap[T](m, err)) {
+				return
+			}
+		}
+	}
+}
+
+// Update updates the resource at path, and returns it. Only the fields
+// of resource that are set are updated, so the fields of T should be
+// tagged with omitempty.
+func (tc *TypedResourceClient[T]) Update(ctx context.Context, path string, resource T, opts ...CallOption) (T, error) {
+	if err := tc.supports("update", tc.Resource.Methods.Update != nil); err != nil {
+		return zero[T](), err
+	}
+	body, err := toMap(resource)
+	if err != nil {
+		return zero[T](), err
+	}
+	body, err = tc.Client.validateRequest(tc.Resource.Schema, body, true)
+	if err != nil {
+		return zero[T](), err
+	}
+	return fromMap[T](tc.Client.update(ctx, &CallInfo{Resource: tc.Resource, Method: "Update"}, tc.ServerUrl, path, body, "", opts...))
+}
+
+func (tc *TypedResourceClient[T]) Apply(ctx context.Context, path string, resource T, opts ...CallOption) (T, error) {
+	if err := tc.supports("apply", tc.Resource.Methods.Apply != nil); err != nil {
+		return zero[T](), err
+	}
+	body, err := toMap(resource)
+	if err != nil {
+		return zero[T](), err
+	}
+	return fromMap[T](tc.Client.Apply(ctx, tc.Resource, tc.ServerUrl, path, body, opts...))
+}
+
+func (tc *TypedResourceClient[T]) Delete(ctx context.Context, path string, opts ...CallOption) error {
+	if err := tc.supports("delete", tc.Resource.Methods.Delete != nil); err != nil {
+		return err
+	}
+	return tc.Client.delete(ctx, &CallInfo{Resource: tc.Resource, Method: "Delete"}, tc.ServerUrl, path, "", opts...)
+}
+
+func (tc *TypedResourceClient[T]) supports(method string, defined bool) error {
+	if !defined {
+		return fmt.Errorf("resource %s does not support the %s method", tc.Resource.Singular, method)
+	}
+	return nil
+}
+
+func zero[T any]() T {
+	var t T
+	return t
+}
+
+// toMap converts a resource to the JSON object it is marshalled to.
+func toMap[T any](resource T) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON: %v", err)
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("error converting %T to a JSON object: %v", resource, err)
+	}
+	return m, nil
+}
+
+// fromMap converts the result of a call to a resource, passing errors
+// through.
+func fromMap[T any](m map[string]interface{}, err error) (T, error) {
+	var resource T
+	if err != nil {
+		return resource, err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return resource, fmt.Errorf("error marshalling JSON: %v", err)
+	}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return resource, fmt.Errorf("error unmarshalling JSON into %T: %v", resource, err)
+	}
+	return resource, nil
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/internal/jsonvalue"
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+)
+
+// ValidationMode configures the validation of request bodies against
+// the schema of their resource, before they are sent.
+type ValidationMode int
+
+const (
+	// ValidateNone sends request bodies as they are.
+	ValidateNone ValidationMode = iota
+	// ValidateRejectReadOnly validates request bodies, and rejects the
+	// read-only fields of the resource, such as path.
+	ValidateRejectReadOnly
+	// ValidateStripReadOnly removes the read-only fields of the resource
+	// from request bodies, and validates the rest.
+	ValidateStripReadOnly
+)
+
+// ValidationError is returned when a request body does not match the
+// schema of the method, before it is sent.
+type ValidationError struct {
+	// Field is the path of the invalid field, e.g. "author.name", or
+	// empty if the body itself is invalid.
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid request: " + e.describe()
+}
+
+func (e *ValidationError) describe() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("field %s %s", e.Field, e.Message)
+}
+
+// ValidationErrors are all the problems of a request body. Use
+// errors.As to inspect each *ValidationError.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	problems := make([]string, len(e))
+	for i, err := range e {
+		problems[i] = err.describe()
+	}
+	return fmt.Sprintf("invalid request: %d errors: %s", len(e), strings.Join(problems, "; "))
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// validator checks values against schemas. References to other schemas
+// are not resolved, so the values of such fields are not checked.
+type validator struct {
+	// partial bodies, such as those of updates, may omit required
+	// fields.
+	partial       bool
+	stripReadOnly bool
+	errs          ValidationErrors
+}
+
+func (v *validator) invalid(field string, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// validateRequest validates the body of a request for a resource with
+// schema s, according to the RequestValidation of the client. It
+// returns the body to send, without read-only fields if they are
+// stripped. The ignored top-level fields are sent without being
+// validated, e.g. the id of a create request.
+func (c *Client) validateRequest(s *openapi.Schema, body map[string]interface{}, partial bool, ignored ...string) (map[string]interface{}, error) {
+	if c.RequestValidation == ValidateNone || s == nil {
+		return body, nil
+	}
+	checked := map[string]interface{}{}
+	for name, value := range body {
+		if !slices.Contains(ignored, name) {
+			checked[name] = value
+		}
+	}
+	checked, err := normalize(checked)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{partial: partial, stripReadOnly: c.RequestValidation == ValidateStripReadOnly}
+	result, _ := v.value("", s, checked).(map[string]interface{})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	for _, name := range ignored {
+		if value, ok := body[name]; ok {
+			result[name] = value
+		}
+	}
+	return result, nil
+}
+
+// normalize returns body as it is sent, so that values of any Go type
+// that marshals to JSON, such as []string or structs, are validated as
+// the JSON values they become. Numbers are decoded as json.Number to
+// keep their precision.
+func normalize(body map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON: %v", err)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	normalized := map[string]interface{}{}
+	if err := d.Decode(&normalized); err != nil {
+		return nil, fmt.Errorf("error unmarshalling JSON: %v", err)
+	}
+	return normalized, nil
+}
+
+// value validates val, and returns it without the read-only fields that
+// are stripped. Objects are copied rather than modified.
+func (v *validator) value(field string, s *openapi.Schema, val interface{}) interface{} {
+	if s.Ref != "" || val == nil {
+		return val
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e interface{}) bool { return jsonvalue.Equal(e, val) }) {
+		v.invalid(field, "must be one of %v, got %v", s.Enum, val)
+	}
+	switch s.Type {
+	case "string":
+		if _, ok := val.(string); !ok {
+			v.invalid(field, "must be a string, got %v", val)
+		}
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			v.invalid(field, "must be a boolean, got %v", val)
+		}
+	case "number", "integer":
+		n, ok := jsonvalue.ToFloat(val)
+		if !ok {
+			v.invalid(field, "must be a number, got %v", val)
+		} else if s.Type == "integer" && n != math.Trunc(n) {
+			v.invalid(field, "must be an integer, got %v", val)
+		}
+	case "array":
+		items, ok := val.([]interface{})
+		if !ok {
+			v.invalid(field, "must be a list, got %v", val)
+			return val
+		}
+		if s.Items == nil {
+			return val
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			result[i] = v.value(fmt.Sprintf("%s[%d]", field, i), s.Items, item)
+		}
+		return result
+	case "object", "":
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			if s.Type != "" {
+				v.invalid(field, "must be an object, got %v", val)
+			}
+			return val
+		}
+		return v.object(field, s, m)
+	}
+	return val
+}
+
+func (v *validator) object(field string, s *openapi.Schema, m map[string]interface{}) map[string]interface{} {
+	prefix := ""
+	if field != "" {
+		prefix = field + "."
+	}
+	if !v.partial {
+		for _, name := range s.Required {
+			if _, ok := m[name]; !ok && !s.Properties[name].ReadOnly {
+				v.invalid(prefix+name, "is required")
+			}
+		}
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := map[string]interface{}{}
+	for _, name := range names {
+		// objects without properties are free-form.
+		if len(s.Properties) == 0 {
+			result[name] = m[name]
+			continue
+		}
+		ps, ok := s.Properties[name]
+		switch {
+		case !ok:
+			v.invalid(prefix+name, "is not a field of the request")
+		case ps.ReadOnly && v.stripReadOnly:
+			continue
+		case ps.ReadOnly:
+			v.invalid(prefix+name, "is read-only")
+		}
+		result[name] = v.value(prefix+name, &ps, m[name])
+	}
+	return result
+}
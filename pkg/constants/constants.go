@@ -1,33 +1,65 @@
 package constants
 
 const (
-	FIELD_FILTER_NAME            = "filter"
-	FIELD_FILTER_NUMBER          = 10022
-	FIELD_FORCE_NAME             = "force"
-	FIELD_FORCE_NUMBER           = 10020
-	FIELD_PARENT_NAME            = "parent"
-	FIELD_PARENT_NUMBER          = 10013
-	FIELD_PATH_NAME              = "path"
-	FIELD_PATH_NUMBER            = 10018
-	FIELD_RESOURCE_NAME          = "resource"
-	FIELD_RESOURCE_NUMBER        = 10015
-	FIELD_RESOURCES_NAME         = "resources"
-	FIELD_RESOURCES_NUMBER       = 10016
-	FIELD_PAGE_TOKEN_NAME        = "page_token"
-	FIELD_PAGE_TOKEN_NUMBER      = 10010
-	FIELD_SKIP_NAME              = "skip"
-	FIELD_SKIP_NUMBER            = 10021
-	FIELD_UPDATE_MASK_NAME       = "update_mask"
-	FIELD_UPDATE_MASK_NUMBER     = 10012
-	FIELD_MAX_PAGE_SIZE_NAME     = "max_page_size"
-	FIELD_MAX_PAGE_SIZE_NUMBER   = 10017
-	FIELD_NEXT_PAGE_TOKEN_NAME   = "next_page_token"
-	FIELD_NEXT_PAGE_TOKEN_NUMBER = 10011
-	FIELD_ID_NAME                = "id"
-	FIELD_ID_NUMBER              = 10014
-	FIELD_UNREACHABLE_NAME       = "unreachable"
-	FIELD_UNREACHABLE_NUMBER     = 10019
-	FIELD_RESULTS_NAME           = "results"
-	FIELD_RESULTS_NUMBER         = 10016
-	// next number: 10023
+	FIELD_FILTER_NAME                 = "filter"
+	FIELD_FILTER_NUMBER               = 10022
+	FIELD_FORCE_NAME                  = "force"
+	FIELD_FORCE_NUMBER                = 10020
+	FIELD_PARENT_NAME                 = "parent"
+	FIELD_PARENT_NUMBER               = 10013
+	FIELD_PATH_NAME                   = "path"
+	FIELD_PATH_NUMBER                 = 10018
+	FIELD_RESOURCE_NAME               = "resource"
+	FIELD_RESOURCE_NUMBER             = 10015
+	FIELD_RESOURCES_NAME              = "resources"
+	FIELD_RESOURCES_NUMBER            = 10016
+	FIELD_PAGE_TOKEN_NAME             = "page_token"
+	FIELD_PAGE_TOKEN_NUMBER           = 10010
+	FIELD_SKIP_NAME                   = "skip"
+	FIELD_SKIP_NUMBER                 = 10021
+	FIELD_UPDATE_MASK_NAME            = "update_mask"
+	FIELD_UPDATE_MASK_NUMBER          = 10012
+	FIELD_MAX_PAGE_SIZE_NAME          = "max_page_size"
+	FIELD_MAX_PAGE_SIZE_NUMBER        = 10017
+	FIELD_NEXT_PAGE_TOKEN_NAME        = "next_page_token"
+	FIELD_NEXT_PAGE_TOKEN_NUMBER      = 10011
+	FIELD_ID_NAME                     = "id"
+	FIELD_ID_NUMBER                   = 10014
+	FIELD_UNREACHABLE_NAME            = "unreachable"
+	FIELD_UNREACHABLE_NUMBER          = 10019
+	FIELD_RESULTS_NAME                = "results"
+	FIELD_RESULTS_NUMBER              = 10016
+	FIELD_PATHS_NAME                  = "paths"
+	FIELD_PATHS_NUMBER                = 10023
+	FIELD_REQUESTS_NAME               = "requests"
+	FIELD_REQUESTS_NUMBER             = 10024
+	FIELD_DELETE_TIME_NAME            = "delete_time"
+	FIELD_DELETE_TIME_NUMBER          = 10025
+	FIELD_EXPIRE_TIME_NAME            = "expire_time"
+	FIELD_EXPIRE_TIME_NUMBER          = 10026
+	FIELD_SHOW_DELETED_NAME           = "show_deleted"
+	FIELD_SHOW_DELETED_NUMBER         = 10027
+	FIELD_REVISION_ID_NAME            = "revision_id"
+	FIELD_REVISION_ID_NUMBER          = 10028
+	FIELD_REVISION_CREATE_TIME_NAME   = "revision_create_time"
+	FIELD_REVISION_CREATE_TIME_NUMBER = 10029
+	FIELD_TAG_NAME                    = "tag"
+	FIELD_TAG_NUMBER                  = 10030
+	FIELD_ETAG_NAME                   = "etag"
+	FIELD_ETAG_NUMBER                 = 10031
+	FIELD_VALIDATE_ONLY_NAME          = "validate_only"
+	FIELD_VALIDATE_ONLY_NUMBER        = 10032
+	FIELD_REQUEST_ID_NAME             = "request_id"
+	FIELD_REQUEST_ID_NUMBER           = 10033
+	FIELD_READ_MASK_NAME              = "read_mask"
+	FIELD_READ_MASK_NUMBER            = 10034
+	FIELD_ORDER_BY_NAME               = "order_by"
+	FIELD_ORDER_BY_NUMBER             = 10035
+	// next number: 10036
+)
+
+const (
+	// HEADER_IF_MATCH carries the etag of a resource on a conditional
+	// request (aep.dev/154).
+	HEADER_IF_MATCH = "If-Match"
 )
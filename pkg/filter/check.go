@@ -0,0 +1,250 @@
+package filter
+
+import (
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+)
+
+type kind int
+
+const (
+	// kindDyn is the kind of values whose type is not known until the
+	// filter is evaluated, e.g. fields of a free-form object.
+	kindDyn kind = iota
+	kindNull
+	kindBool
+	kindNumber
+	kindString
+	kindList
+	kindObject
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindNull:
+		return "null"
+	case kindBool:
+		return "bool"
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindList:
+		return "list"
+	case kindObject:
+		return "object"
+	}
+	return "dyn"
+}
+
+// exprType is the type of an expression. schema is set for field
+// references, so that nested fields can be resolved, and elem is the
+// kind of the elements of a list.
+type exprType struct {
+	kind   kind
+	elem   kind
+	schema *openapi.Schema
+}
+
+var dyn = exprType{kind: kindDyn}
+
+// Check checks that a filter only references fields of the schema, and
+// that the operators and functions are applied to values of the right
+// type. The filter must evaluate to a boolean.
+func Check(expr Expr, schema *openapi.Schema) error {
+	t, err := typeOf(expr, schema)
+	if err != nil {
+		return err
+	}
+	if t.kind != kindBool && t.kind != kindDyn {
+		return errorf(expr.Pos(), "filter must be a boolean expression, got %v", t.kind)
+	}
+	return nil
+}
+
+func typeOfSchema(s *openapi.Schema) exprType {
+	t := exprType{schema: s}
+	switch s.Type {
+	case "string":
+		t.kind = kindString
+	case "integer", "number":
+		t.kind = kindNumber
+	case "boolean":
+		t.kind = kindBool
+	case "array":
+		t.kind = kindList
+		t.elem = kindDyn
+		if s.Items != nil {
+			t.elem = typeOfSchema(s.Items).kind
+		}
+	case "object":
+		t.kind = kindObject
+	case "":
+		// references to other schemas are not resolved.
+		t.kind = kindDyn
+		if s.Ref == "" && s.Properties != nil {
+			t.kind = kindObject
+		}
+	}
+	return t
+}
+
+// field returns the type of the field of an object.
+func field(t exprType, name string, offset int) (exprType, error) {
+	switch t.kind {
+	case kindDyn:
+		return dyn, nil
+	case kindObject:
+		// objects without properties are free-form.
+		if t.schema == nil || t.schema.Properties == nil {
+			return dyn, nil
+		}
+		s, ok := t.schema.Properties[name]
+		if !ok {
+			return dyn, errorf(offset, "unknown field %q", name)
+		}
+		return typeOfSchema(&s), nil
+	}
+	return dyn, errorf(offset, "cannot select field %q of a %v", name, t.kind)
+}
+
+func typeOf(expr Expr, schema *openapi.Schema) (exprType, error) {
+	switch e := expr.(type) {
+	case *Ident:
+		return field(exprType{kind: kindObject, schema: schema}, e.Name, e.Offset)
+	case *Select:
+		t, err := typeOf(e.Operand, schema)
+		if err != nil {
+			return dyn, err
+		}
+		return field(t, e.Field, e.Offset)
+	case *Literal:
+		switch e.Value.(type) {
+		case nil:
+			return exprType{kind: kindNull}, nil
+		case bool:
+			return exprType{kind: kindBool}, nil
+		case float64:
+			return exprType{kind: kindNumber}, nil
+		case string:
+			return exprType{kind: kindString}, nil
+		}
+		return dyn, errorf(e.Offset, "unsupported literal %v", e.Value)
+	case *List:
+		t := exprType{kind: kindList, elem: kindDyn}
+		for i, el := range e.Elements {
+			et, err := typeOf(el, schema)
+			if err != nil {
+				return dyn, err
+			}
+			if i == 0 {
+				t.elem = et.kind
+			} else if et.kind != t.elem {
+				t.elem = kindDyn
+			}
+		}
+		return t, nil
+	case *Unary:
+		t, err := typeOf(e.Operand, schema)
+		if err != nil {
+			return dyn, err
+		}
+		want := kindBool
+		if e.Op == "-" {
+			want = kindNumber
+		}
+		if t.kind != want && t.kind != kindDyn {
+			return dyn, errorf(e.Offset, "operator %s requires a %v, got %v", e.Op, want, t.kind)
+		}
+		return exprType{kind: want}, nil
+	case *Binary:
+		return typeOfBinary(e, schema)
+	case *Call:
+		return typeOfCall(e, schema)
+	}
+	return dyn, errorf(expr.Pos(), "unsupported expression %v", expr)
+}
+
+// comparable returns true if values of kinds a and b can be compared
+// for equality. Any value can be compared to null.
+func comparable(a, b kind) bool {
+	return a == b || a == kindDyn || b == kindDyn || a == kindNull || b == kindNull
+}
+
+func typeOfBinary(e *Binary, schema *openapi.Schema) (exprType, error) {
+	left, err := typeOf(e.Left, schema)
+	if err != nil {
+		return dyn, err
+	}
+	right, err := typeOf(e.Right, schema)
+	if err != nil {
+		return dyn, err
+	}
+	boolean := exprType{kind: kindBool}
+	switch e.Op {
+	case "&&", "||":
+		for _, t := range []exprType{left, right} {
+			if t.kind != kindBool && t.kind != kindDyn {
+				return dyn, errorf(e.Offset, "operator %s requires booleans, got %v", e.Op, t.kind)
+			}
+		}
+	case "==", "!=":
+		if !comparable(left.kind, right.kind) {
+			return dyn, errorf(e.Offset, "cannot compare %v and %v", left.kind, right.kind)
+		}
+	case "<", "<=", ">", ">=":
+		ordered := func(k kind) bool {
+			return k == kindNumber || k == kindString || k == kindDyn
+		}
+		if !ordered(left.kind) || !ordered(right.kind) || !comparable(left.kind, right.kind) {
+			return dyn, errorf(e.Offset, "operator %s requires two numbers or two strings, got %v and %v", e.Op, left.kind, right.kind)
+		}
+	case "in":
+		if right.kind != kindList && right.kind != kindDyn {
+			return dyn, errorf(e.Offset, "operator in requires a list, got %v", right.kind)
+		}
+		if right.kind == kindList && !comparable(left.kind, right.elem) {
+			return dyn, errorf(e.Offset, "cannot look for a %v in a list of %v", left.kind, right.elem)
+		}
+	default:
+		return dyn, errorf(e.Offset, "unsupported operator %s", e.Op)
+	}
+	return boolean, nil
+}
+
+func typeOfCall(e *Call, schema *openapi.Schema) (exprType, error) {
+	args := make([]exprType, len(e.Args))
+	for i, arg := range e.Args {
+		t, err := typeOf(arg, schema)
+		if err != nil {
+			return dyn, err
+		}
+		args[i] = t
+	}
+	switch e.Function {
+	case "startsWith", "endsWith", "contains":
+		if e.Target == nil {
+			return dyn, errorf(e.Offset, "%s must be called on a string", e.Function)
+		}
+		target, err := typeOf(e.Target, schema)
+		if err != nil {
+			return dyn, err
+		}
+		if target.kind != kindString && target.kind != kindDyn {
+			return dyn, errorf(e.Offset, "%s must be called on a string, got %v", e.Function, target.kind)
+		}
+		if len(args) != 1 || (args[0].kind != kindString && args[0].kind != kindDyn) {
+			return dyn, errorf(e.Offset, "%s requires a single string argument", e.Function)
+		}
+		return exprType{kind: kindBool}, nil
+	case "size":
+		if e.Target != nil || len(args) != 1 {
+			return dyn, errorf(e.Offset, "size requires a single argument")
+		}
+		switch args[0].kind {
+		case kindString, kindList, kindObject, kindDyn:
+			return exprType{kind: kindNumber}, nil
+		}
+		return dyn, errorf(e.Offset, "size requires a string, list or object, got %v", args[0].kind)
+	}
+	return dyn, errorf(e.Offset, "unknown function %q", e.Function)
+}
@@ -0,0 +1,226 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aep-dev/aep-lib-go/pkg/internal/jsonvalue"
+)
+
+// Evaluate returns true if the resource matches the filter. Resources
+// are decoded JSON objects; fields that are not set are null, which is
+// false where a boolean is expected.
+//
+// Filters should be checked with Check first: Evaluate returns an error
+// if an operator is applied to values of the wrong type.
+func Evaluate(expr Expr, resource map[string]interface{}) (bool, error) {
+	v, err := eval(expr, resource)
+	if err != nil {
+		return false, err
+	}
+	if b, ok := toBool(v); ok {
+		return b, nil
+	}
+	return false, errorf(expr.Pos(), "filter must be a boolean expression, got %v", v)
+}
+
+func eval(expr Expr, resource map[string]interface{}) (interface{}, error) {
+	switch e := expr.(type) {
+	case *Ident:
+		return resource[e.Name], nil
+	case *Select:
+		v, err := eval(e.Operand, resource)
+		if err != nil {
+			return nil, err
+		}
+		switch o := v.(type) {
+		case nil:
+			return nil, nil
+		case map[string]interface{}:
+			return o[e.Field], nil
+		}
+		return nil, errorf(e.Offset, "cannot select field %q of %v", e.Field, v)
+	case *Literal:
+		return e.Value, nil
+	case *List:
+		l := make([]interface{}, len(e.Elements))
+		for i, el := range e.Elements {
+			v, err := eval(el, resource)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = v
+		}
+		return l, nil
+	case *Unary:
+		v, err := eval(e.Operand, resource)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == "-" {
+			n, ok := jsonvalue.ToFloat(v)
+			if !ok {
+				return nil, errorf(e.Offset, "operator - requires a number, got %v", v)
+			}
+			return -n, nil
+		}
+		b, ok := toBool(v)
+		if !ok {
+			return nil, errorf(e.Offset, "operator ! requires a bool, got %v", v)
+		}
+		return !b, nil
+	case *Binary:
+		return evalBinary(e, resource)
+	case *Call:
+		return evalCall(e, resource)
+	}
+	return nil, errorf(expr.Pos(), "unsupported expression %v", expr)
+}
+
+func evalBool(expr Expr, resource map[string]interface{}, op string) (bool, error) {
+	v, err := eval(expr, resource)
+	if err != nil {
+		return false, err
+	}
+	b, ok := toBool(v)
+	if !ok {
+		return false, errorf(expr.Pos(), "operator %s requires booleans, got %v", op, v)
+	}
+	return b, nil
+}
+
+// toBool converts a boolean or null to a bool.
+func toBool(v interface{}) (bool, bool) {
+	if v == nil {
+		return false, true
+	}
+	b, ok := v.(bool)
+	return b, ok
+}
+
+func evalBinary(e *Binary, resource map[string]interface{}) (interface{}, error) {
+	// the logical operators short-circuit.
+	if e.Op == "&&" || e.Op == "||" {
+		left, err := evalBool(e.Left, resource, e.Op)
+		if err != nil {
+			return nil, err
+		}
+		if left == (e.Op == "||") {
+			return left, nil
+		}
+		return evalBool(e.Right, resource, e.Op)
+	}
+	left, err := eval(e.Left, resource)
+	if err != nil {
+		return nil, err
+	}
+	right, err := eval(e.Right, resource)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Op {
+	case "==":
+		return jsonvalue.Equal(left, right), nil
+	case "!=":
+		return !jsonvalue.Equal(left, right), nil
+	case "in":
+		if right == nil {
+			return false, nil
+		}
+		l := reflect.ValueOf(right)
+		if l.Kind() != reflect.Slice && l.Kind() != reflect.Array {
+			return nil, errorf(e.Offset, "operator in requires a list, got %v", right)
+		}
+		for i := 0; i < l.Len(); i++ {
+			if jsonvalue.Equal(left, l.Index(i).Interface()) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	// unset fields are not ordered.
+	if left == nil || right == nil {
+		return false, nil
+	}
+	var cmp int
+	ln, lok := jsonvalue.ToFloat(left)
+	rn, rok := jsonvalue.ToFloat(right)
+	ls, lsok := left.(string)
+	rs, rsok := right.(string)
+	switch {
+	case lok && rok:
+		if ln < rn {
+			cmp = -1
+		} else if ln > rn {
+			cmp = 1
+		}
+	case lsok && rsok:
+		cmp = strings.Compare(ls, rs)
+	default:
+		return nil, errorf(e.Offset, "operator %s requires two numbers or two strings, got %v and %v", e.Op, left, right)
+	}
+	switch e.Op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return nil, errorf(e.Offset, "unsupported operator %s", e.Op)
+}
+
+func evalCall(e *Call, resource map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(e.Args))
+	for i, arg := range e.Args {
+		v, err := eval(arg, resource)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	switch e.Function {
+	case "startsWith", "endsWith", "contains":
+		if e.Target == nil || len(args) != 1 {
+			return nil, errorf(e.Offset, "%s must be called on a string, with a single argument", e.Function)
+		}
+		target, err := eval(e.Target, resource)
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return false, nil
+		}
+		s, ok := target.(string)
+		arg, argOk := args[0].(string)
+		if !ok || !argOk {
+			return nil, errorf(e.Offset, "%s requires strings, got %v and %v", e.Function, target, args[0])
+		}
+		switch e.Function {
+		case "startsWith":
+			return strings.HasPrefix(s, arg), nil
+		case "endsWith":
+			return strings.HasSuffix(s, arg), nil
+		}
+		return strings.Contains(s, arg), nil
+	case "size":
+		if e.Target != nil || len(args) != 1 {
+			return nil, errorf(e.Offset, "size requires a single argument")
+		}
+		switch v := args[0].(type) {
+		case nil:
+			return float64(0), nil
+		case string:
+			return float64(utf8.RuneCountInString(v)), nil
+		}
+		switch v := reflect.ValueOf(args[0]); v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return float64(v.Len()), nil
+		}
+		return nil, errorf(e.Offset, "size requires a string, list or object, got %v", args[0])
+	}
+	return nil, errorf(e.Offset, "unknown function %q", e.Function)
+}
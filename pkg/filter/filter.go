@@ -0,0 +1,155 @@
+// Package filter implements the filter expressions of List methods
+// (aep.dev/160).
+//
+// Filters are written in a subset of the Common Expression Language
+// (CEL), e.g.
+//
+//	title.startsWith("The") && (price < 20.5 || author.name == "Ann")
+//
+// The subset supports:
+//   - field references, with nested fields separated by dots.
+//   - string, number, boolean and null literals, and lists of literals.
+//   - the comparison operators ==, !=, <, <=, > and >=.
+//   - the logical operators &&, || and !.
+//   - the in operator, e.g. status in ["ACTIVE", "PENDING"], or
+//     "fiction" in tags.
+//   - the startsWith, endsWith and contains string methods, and the
+//     size function.
+//
+// A filter is parsed with Parse, checked against the schema of a
+// resource with Check, and evaluated against a resource with Evaluate.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is a node of a parsed filter expression.
+type Expr interface {
+	// String returns the expression in filter syntax.
+	String() string
+	// Pos returns the offset of the expression in the filter.
+	Pos() int
+}
+
+// Ident is a reference to a top-level field of the resource.
+type Ident struct {
+	Name   string
+	Offset int
+}
+
+// Select is a reference to a field of an object, e.g. author.name.
+type Select struct {
+	Operand Expr
+	Field   string
+	Offset  int
+}
+
+// Literal is a string, number (float64), boolean or null (nil) value.
+type Literal struct {
+	Value  interface{}
+	Offset int
+}
+
+// List is a list of expressions, e.g. ["a", "b"].
+type List struct {
+	Elements []Expr
+	Offset   int
+}
+
+// Unary is the application of a prefix operator ("!" or "-").
+type Unary struct {
+	Op      string
+	Operand Expr
+	Offset  int
+}
+
+// Binary is the application of an infix operator, e.g. "==" or "&&".
+type Binary struct {
+	Op     string
+	Left   Expr
+	Right  Expr
+	Offset int
+}
+
+// Call is a function call. Target is nil for global functions, e.g.
+// size(tags), and the receiver for methods, e.g. title.startsWith("a").
+type Call struct {
+	Target   Expr
+	Function string
+	Args     []Expr
+	Offset   int
+}
+
+func (e *Ident) Pos() int   { return e.Offset }
+func (e *Select) Pos() int  { return e.Offset }
+func (e *Literal) Pos() int { return e.Offset }
+func (e *List) Pos() int    { return e.Offset }
+func (e *Unary) Pos() int   { return e.Offset }
+func (e *Binary) Pos() int  { return e.Offset }
+func (e *Call) Pos() int    { return e.Offset }
+
+func (e *Ident) String() string {
+	return e.Name
+}
+
+func (e *Select) String() string {
+	return e.Operand.String() + "." + e.Field
+}
+
+func (e *Literal) String() string {
+	switch v := e.Value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (e *List) String() string {
+	return "[" + joinExprs(e.Elements) + "]"
+}
+
+func (e *Unary) String() string {
+	return e.Op + e.Operand.String()
+}
+
+func (e *Binary) String() string {
+	return "(" + e.Left.String() + " " + e.Op + " " + e.Right.String() + ")"
+}
+
+func (e *Call) String() string {
+	call := e.Function + "(" + joinExprs(e.Args) + ")"
+	if e.Target != nil {
+		return e.Target.String() + "." + call
+	}
+	return call
+}
+
+func joinExprs(exprs []Expr) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = e.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Error is an error in a filter, at the given offset.
+type Error struct {
+	Offset  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Offset, e.Message)
+}
+
+func errorf(offset int, format string, args ...interface{}) *Error {
+	return &Error{Offset: offset, Message: fmt.Sprintf(format, args...)}
+}
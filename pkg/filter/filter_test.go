@@ -0,0 +1,214 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var bookSchema = &openapi.Schema{
+	Type: "object",
+	Properties: map[string]openapi.Schema{
+		"title":     {Type: "string"},
+		"price":     {Type: "number"},
+		"pages":     {Type: "integer"},
+		"published": {Type: "boolean"},
+		"tags":      {Type: "array", Items: &openapi.Schema{Type: "string"}},
+		"author": {
+			Type: "object",
+			Properties: map[string]openapi.Schema{
+				"name": {Type: "string"},
+			},
+		},
+		"labels":    {Type: "object"},
+		"publisher": {Ref: "#/components/schemas/publisher"},
+	},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{"comparison", `title == "a"`, `(title == "a")`},
+		{"nested field", `author.name != 'Ann'`, `(author.name != "Ann")`},
+		{"precedence", `a || b && c`, `(a || (b && c))`},
+		{"parentheses", `(a || b) && c`, `((a || b) && c)`},
+		{"not", `!published`, `!published`},
+		{"negative number", `price > -1.5`, `(price > -1.5)`},
+		{"in", `"x" in ["x", 'y']`, `("x" in ["x", "y"])`},
+		{"method", `title.startsWith("The")`, `title.startsWith("The")`},
+		{"function", `size(tags) >= 2`, `(size(tags) >= 2)`},
+		{"escapes", `title == "a\"b"`, `(title == "a\"b")`},
+		{"literals", `a == null || b == true`, `((a == null) || (b == true))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.filter)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, e.String())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		wantErr  string
+		position int
+	}{
+		{"empty", ``, "unexpected end of filter", 0},
+		{"unterminated string", `title == "a`, "unterminated string", 9},
+		{"unknown character", `title = "a"`, "unexpected character '='", 6},
+		{"missing operand", `title ==`, "unexpected end of filter", 8},
+		{"unbalanced parentheses", `(a || b`, `expected ")", got end of filter`, 7},
+		{"trailing tokens", `a b`, `unexpected "b"`, 2},
+		{"missing comma", `a in ["x" "y"]`, `expected ",", got "y"`, 10},
+		{"bad field", `author.1`, `expected a field name, got "1"`, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.filter)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+				var ferr *Error
+				if assert.ErrorAs(t, err, &ferr) {
+					assert.Equal(t, tt.position, ferr.Offset)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr string
+	}{
+		{"string comparison", `title == "a"`, ""},
+		{"number comparison", `price < 10 && pages >= 100`, ""},
+		{"nested field", `author.name.endsWith("n")`, ""},
+		{"free-form object", `labels.env == "prod"`, ""},
+		{"reference", `publisher.name == "p"`, ""},
+		{"null", `title != null`, ""},
+		{"in list literal", `title in ["a", "b"]`, ""},
+		{"in list field", `"a" in tags`, ""},
+		{"size", `size(tags) > 1 && size(title) < 10`, ""},
+		{"boolean field", `published`, ""},
+		{"unknown field", `isbn == "1"`, `unknown field "isbn"`},
+		{"unknown nested field", `author.age > 1`, `unknown field "age"`},
+		{"select on a string", `title.length == 1`, `cannot select field "length" of a string`},
+		{"mismatched comparison", `title == 1`, "cannot compare string and number"},
+		{"ordering booleans", `published < true`, "requires two numbers or two strings"},
+		{"logical on a string", `title && published`, "operator && requires booleans, got string"},
+		{"not on a number", `!price`, "operator ! requires a bool, got number"},
+		{"in a string", `"a" in title`, "operator in requires a list, got string"},
+		{"in mismatched list", `price in ["a"]`, "cannot look for a number in a list of string"},
+		{"method on a number", `price.startsWith("1")`, "startsWith must be called on a string, got number"},
+		{"method argument", `title.contains(1)`, "contains requires a single string argument"},
+		{"size of a number", `size(price) > 1`, "size requires a string, list or object, got number"},
+		{"unknown function", `matches(title, "a")`, `unknown function "matches"`},
+		{"not boolean", `price`, "filter must be a boolean expression, got number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+			err = Check(e, bookSchema)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	book := map[string]interface{}{
+		"title":     "The Hobbit",
+		"price":     12.5,
+		"pages":     310,
+		"published": true,
+		"tags":      []interface{}{"fantasy", "classic"},
+		"author": map[string]interface{}{
+			"name": "Tolkien",
+		},
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{"equal", `title == "The Hobbit"`, true},
+		{"not equal", `title != "The Hobbit"`, false},
+		{"integer field", `pages == 310`, true},
+		{"ordering", `price < 20 && pages > 300`, true},
+		{"string ordering", `title >= "The"`, true},
+		{"or", `price > 20 || published`, true},
+		{"not", `!published`, false},
+		{"negative", `-price < 0`, true},
+		{"nested field", `author.name == "Tolkien"`, true},
+		{"in list literal", `title in ["Dune", "The Hobbit"]`, true},
+		{"in list field", `"classic" in tags`, true},
+		{"not in list field", `"horror" in tags`, false},
+		{"startsWith", `title.startsWith("The")`, true},
+		{"endsWith", `title.endsWith("The")`, false},
+		{"contains", `author.name.contains("olk")`, true},
+		{"size", `size(tags) == 2 && size(title) == 10`, true},
+		{"unset field is null", `isbn == null`, true},
+		{"unset nested field is null", `series.name == null`, true},
+		{"unset field is not ordered", `rating > 1 || rating <= 1`, false},
+		{"unset field does not start with", `isbn.startsWith("1")`, false},
+		{"short-circuit", `published || title`, true},
+		{"not unset field", `!archived`, true},
+		{"unset field and", `archived && true`, false},
+		{"and unset field", `true && archived`, false},
+		{"unset field or", `archived || price > 1`, true},
+		{"or unset field", `price > 1 || archived`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := Evaluate(e, book)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr string
+	}{
+		{"logical on a string", `title && true`, "operator && requires booleans"},
+		{"ordering mismatched types", `title < 1`, "requires two numbers or two strings"},
+		{"select on a string", `title.length == 1`, `cannot select field "length"`},
+		{"not boolean", `title`, "filter must be a boolean expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = Evaluate(e, map[string]interface{}{"title": "a"})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
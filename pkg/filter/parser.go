@@ -0,0 +1,340 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// operators are matched longest first.
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "-", "(", ")", "[", "]", ",", "."}
+
+var relations = []string{"==", "!=", "<", "<=", ">", ">="}
+
+func isLetter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// lex splits a filter into tokens.
+func lex(s string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isLetter(c):
+			start := i
+			for i < len(s) && (isLetter(s[i]) || isDigit(s[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, s[start:i], start})
+		case isDigit(c):
+			start := i
+			for i < len(s) && (isDigit(s[i]) || s[i] == '.' || s[i] == 'e' || s[i] == 'E' ||
+				((s[i] == '+' || s[i] == '-') && (s[i-1] == 'e' || s[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, s[start:i], start})
+		case c == '"' || c == '\'':
+			start := i
+			value, n, err := unquote(s[i:])
+			if err != nil {
+				return nil, errorf(start, "%v", err)
+			}
+			i += n
+			tokens = append(tokens, token{tokenString, value, start})
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					tokens = append(tokens, token{tokenOperator, op, i})
+					i += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, errorf(i, "unexpected character %q", c)
+			}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(s)}), nil
+}
+
+// unquote returns the value of the string literal at the start of s,
+// and its length.
+func unquote(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] == quote {
+			return b.String(), i + 1, nil
+		}
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			break
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '\\', '"', '\'':
+			b.WriteByte(s[i])
+		default:
+			return "", 0, fmt.Errorf("unknown escape sequence \\%c", s[i])
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a filter into an expression.
+func Parse(filter string) (Expr, error) {
+	tokens, err := lex(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorf(t.offset, "unexpected %q", t.text)
+	}
+	return e, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// peekOperator returns true if the next token is the operator op.
+func (p *parser) peekOperator(op string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && t.text == op
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *parser) accept(op string) bool {
+	if p.peekOperator(op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		if t.kind == tokenEOF {
+			return errorf(t.offset, "expected %q, got end of filter", op)
+		}
+		return errorf(t.offset, "expected %q, got %q", op, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("||") {
+		t := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: t.text, Left: left, Right: right, Offset: t.offset}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("&&") {
+		t := p.next()
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: t.text, Left: left, Right: right, Offset: t.offset}
+	}
+	return left, nil
+}
+
+func (p *parser) parseRelation() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		isRelation := t.kind == tokenOperator && slices.Contains(relations, t.text)
+		isIn := t.kind == tokenIdent && t.text == "in"
+		if !isRelation && !isIn {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: t.text, Left: left, Right: right, Offset: t.offset}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	t := p.peek()
+	if p.peekOperator("!") || p.peekOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Op: t.text, Operand: operand, Offset: t.offset}, nil
+	}
+	return p.parseMember()
+}
+
+func (p *parser) parseMember() (Expr, error) {
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator(".") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenIdent {
+			return nil, errorf(t.offset, "expected a field name, got %q", t.text)
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			e = &Call{Target: e, Function: t.text, Args: args, Offset: t.offset}
+		} else {
+			e = &Select{Operand: e, Field: t.text, Offset: t.offset}
+		}
+	}
+	return e, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{Value: t.text, Offset: t.offset}, nil
+	case tokenNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, errorf(t.offset, "invalid number %q", t.text)
+		}
+		return &Literal{Value: v, Offset: t.offset}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &Literal{Value: t.text == "true", Offset: t.offset}, nil
+		case "null":
+			return &Literal{Value: nil, Offset: t.offset}, nil
+		case "in":
+			return nil, errorf(t.offset, "unexpected %q", t.text)
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			return &Call{Function: t.text, Args: args, Offset: t.offset}, nil
+		}
+		return &Ident{Name: t.text, Offset: t.offset}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			e, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		case "[":
+			elements, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return &List{Elements: elements, Offset: t.offset}, nil
+		}
+	case tokenEOF:
+		return nil, errorf(t.offset, "unexpected end of filter")
+	}
+	return nil, errorf(t.offset, "unexpected %q", t.text)
+}
+
+// parseArgs parses a comma-separated list of expressions, up to and
+// including the closing operator.
+func (p *parser) parseArgs(closing string) ([]Expr, error) {
+	args := []Expr{}
+	if p.accept(closing) {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(closing) {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package jsonvalue compares the values of decoded JSON documents, such
+// as resources and request bodies.
+package jsonvalue
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ToFloat converts the numeric types a decoded JSON value may contain
+// to a float64.
+func ToFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Equal compares values, regardless of their numeric types.
+func Equal(a, b interface{}) bool {
+	an, aok := ToFloat(a)
+	bn, bok := ToFloat(b)
+	if aok && bok {
+		return an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,28 @@
+package jsonvalue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b interface{}
+		want bool
+	}{
+		{"float and int", 1.0, 1, true},
+		{"json number and float", json.Number("2.5"), 2.5, true},
+		{"different numbers", int64(1), float32(2), false},
+		{"strings", "a", "a", true},
+		{"number and string", 1, "1", false},
+		{"lists", []interface{}{"a"}, []interface{}{"a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Equal(tt.a, tt.b))
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"os"
 	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/yamlutil"
 )
 
 const (
@@ -150,6 +152,9 @@ type Parameter struct {
 	Schema      *Schema    `json:"schema,omitempty"`
 	Type        string     `json:"type,omitempty"`
 	XAEPField   *XAEPField `json:"x-aep-field,omitempty"`
+	// XAEPOrderableFields lists the fields by which the order_by
+	// parameter of a List method can order the results.
+	XAEPOrderableFields []string `json:"x-aep-orderable-fields,omitempty"`
 }
 
 type Response struct {
@@ -174,6 +179,7 @@ type MediaType struct {
 type Schema struct {
 	Type         string        `json:"type,omitempty"`
 	Format       string        `json:"format,omitempty"`
+	Enum         []interface{} `json:"enum,omitempty"`
 	Items        *Schema       `json:"items,omitempty"`
 	Properties   Properties    `json:"properties,omitempty"`
 	Ref          string        `json:"$ref,omitempty"`
@@ -219,17 +225,29 @@ type XAEPLongRunningOperationResponse struct {
 	Schema *Schema `json:"schema,omitempty"`
 }
 
+// FetchOpenAPI reads an OpenAPI document from a file or URL. Both
+// JSON and YAML documents are supported; the format is detected from
+// the content.
 func FetchOpenAPI(pathOrURL string) (*OpenAPI, error) {
 	body, err := readFileOrURL(pathOrURL)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read file or URL: %w", err)
 	}
+	return ParseOpenAPI(body)
+}
 
+// ParseOpenAPI parses an OpenAPI document in either JSON or YAML format.
+func ParseOpenAPI(data []byte) (*OpenAPI, error) {
 	var api OpenAPI
-	if err := json.Unmarshal(body, &api); err != nil {
-		return nil, err
+	if yamlutil.IsJSON(data) {
+		if err := json.Unmarshal(data, &api); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := yamlutil.Unmarshal(data, &api); err != nil {
+			return nil, err
+		}
 	}
-
 	return &api, nil
 }
 
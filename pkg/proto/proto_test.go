@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
 	"github.com/jhump/protoreflect/desc/builder"
 	"github.com/stretchr/testify/assert"
 )
@@ -287,3 +288,253 @@ func TestJsonNameLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceDescriptorMultiplePatterns(t *testing.T) {
+	a := &api.API{
+		Name: "example.com",
+		Resources: map[string]*api.Resource{
+			"book": {
+				Singular: "book",
+				Plural:   "books",
+				Patterns: []string{
+					"publishers/{publisher_id}/books/{book_id}",
+					"authors/{author_id}/books/{book_id}",
+				},
+				Schema: &openapi.Schema{Type: "object"},
+			},
+		},
+	}
+	rd := resourceDescriptor(a, a.Resources["book"])
+	assert.Equal(t, []string{
+		"publishers/{publisher_id}/books/{book_id}",
+		"authors/{author_id}/books/{book_id}",
+	}, rd.Pattern)
+}
+
+func TestMultipleParentsHTTPBindings(t *testing.T) {
+	publisher := &api.Resource{Singular: "publisher", Plural: "publishers"}
+	author := &api.Resource{Singular: "author", Plural: "authors"}
+	book := &api.Resource{Singular: "book", Plural: "books", Parents: []string{"publisher", "author"}}
+	a := &api.API{
+		Name: "example.com",
+		Resources: map[string]*api.Resource{
+			"publisher": publisher,
+			"author":    author,
+			"book":      book,
+		},
+	}
+	for _, r := range a.Resources {
+		r.Schema = &openapi.Schema{Type: "object"}
+		r.Methods = api.Methods{Get: &api.GetMethod{}, List: &api.ListMethod{}}
+	}
+	assert.NoError(t, api.AddImplicitFieldsAndValidate(a))
+
+	assert.Equal(t, []string{"publishers/*/books/*", "authors/*/books/*"}, generateHTTPPaths(book))
+	assert.Equal(t, []string{
+		"/{parent=publishers/*}/books",
+		"/{parent=authors/*}/books",
+	}, generateParentHTTPPaths(book))
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	t.Logf("Proto content: \n---\n%s\n---", protoContent)
+	assert.Contains(t, protoContent, `get: "/{path=publishers/*/books/*}"`)
+	assert.Contains(t, protoContent, `additional_bindings: [ { get: "/{path=authors/*/books/*}" } ]`)
+	assert.Contains(t, protoContent, `additional_bindings: [ { get: "/{parent=authors/*}/books" } ]`)
+}
+
+func TestCollectionCustomMethods(t *testing.T) {
+	a := api.ExampleAPI()
+	a.Resources["book"].CollectionCustomMethods = []*api.CustomMethod{
+		{
+			Name:   "search",
+			Method: "POST",
+			Request: &openapi.Schema{
+				Type: "object",
+				Properties: map[string]openapi.Schema{
+					"query": {Type: "string", XAEPField: &openapi.XAEPField{FieldNumber: 1}},
+				},
+			},
+			Response: &openapi.Schema{Type: "object"},
+		},
+	}
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	assert.Contains(t, protoContent, "rpc SearchBooks ( SearchBooksRequest ) returns ( SearchBooksResponse )")
+	assert.Contains(t, protoContent, `post: "/{parent=publishers/*}/books:search"`)
+	assert.Contains(t, protoContent, "message SearchBooksRequest")
+}
+
+func TestSingletonResource(t *testing.T) {
+	a := &api.API{
+		Name: "example.com",
+		Resources: map[string]*api.Resource{
+			"publisher": {
+				Singular: "publisher",
+				Plural:   "publishers",
+				Schema:   &openapi.Schema{Type: "object"},
+				Methods:  api.Methods{Get: &api.GetMethod{}},
+			},
+			"publisher-config": {
+				Singular:  "publisher-config",
+				Plural:    "publisher-configs",
+				Parents:   []string{"publisher"},
+				Singleton: true,
+				Schema:    &openapi.Schema{Type: "object"},
+				Methods:   api.Methods{Get: &api.GetMethod{}, Update: &api.UpdateMethod{}},
+			},
+		},
+	}
+	assert.NoError(t, api.AddImplicitFieldsAndValidate(a))
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	assert.Contains(t, protoContent, `pattern: [ "publishers/{publisher_id}/config" ]`)
+	assert.Contains(t, protoContent, `get: "/{path=publishers/*/config}"`)
+	assert.Contains(t, protoContent, `patch: "/{path=publishers/*/config}"`)
+	assert.NotContains(t, protoContent, "CreatePublisherConfig")
+}
+
+func TestBatchMethods(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.BatchGet = &api.BatchGetMethod{}
+	book.Methods.BatchCreate = &api.BatchCreateMethod{}
+	book.Methods.BatchUpdate = &api.BatchUpdateMethod{}
+	book.Methods.BatchDelete = &api.BatchDeleteMethod{IsLongRunning: true}
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	for _, expected := range []string{
+		"rpc BatchGetBooks ( BatchGetBooksRequest ) returns ( BatchGetBooksResponse )",
+		`get: "/{parent=publishers/*}/books:batchGet"`,
+		"rpc BatchCreateBooks ( BatchCreateBooksRequest ) returns ( BatchCreateBooksResponse )",
+		`post: "/{parent=publishers/*}/books:batchCreate"`,
+		"repeated CreateBookRequest requests = 10024",
+		"rpc BatchUpdateBooks ( BatchUpdateBooksRequest ) returns ( BatchUpdateBooksResponse )",
+		"repeated UpdateBookRequest requests = 10024",
+		"rpc BatchDeleteBooks ( BatchDeleteBooksRequest ) returns ( aep.api.Operation )",
+		`post: "/{parent=publishers/*}/books:batchDelete"`,
+		"repeated string paths = 10023",
+	} {
+		assert.Contains(t, protoContent, expected)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Delete.SupportsSoftDelete = true
+	book.Methods.Undelete = &api.UndeleteMethod{IsLongRunning: true}
+	assert.NoError(t, api.AddImplicitFieldsAndValidate(a))
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	for _, expected := range []string{
+		"rpc DeleteBook ( DeleteBookRequest ) returns ( Book )",
+		"rpc UndeleteBook ( UndeleteBookRequest ) returns ( aep.api.Operation )",
+		`post: "/{path=publishers/*/books/*}:undelete"`,
+		"bool show_deleted = 10027",
+		"delete_time = 10025",
+		"expire_time = 10026",
+	} {
+		assert.Contains(t, protoContent, expected)
+	}
+	assert.NotContains(t, protoContent, "UndeletePublisher")
+}
+
+func TestRevisionMethods(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.ListRevisions = &api.ListRevisionsMethod{}
+	book.Methods.GetRevision = &api.GetRevisionMethod{}
+	book.Methods.Commit = &api.CommitMethod{}
+	book.Methods.Rollback = &api.RollbackMethod{IsLongRunning: true}
+	book.Methods.TagRevision = &api.TagRevisionMethod{}
+	assert.NoError(t, api.AddImplicitFieldsAndValidate(a))
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	for _, expected := range []string{
+		"rpc ListBookRevisions ( ListBookRevisionsRequest ) returns ( ListBookRevisionsResponse )",
+		`get: "/{path=publishers/*/books/*}/revisions"`,
+		"rpc GetBookRevision ( GetBookRevisionRequest ) returns ( Book )",
+		`get: "/{path=publishers/*/books/*}@{revision_id}"`,
+		"rpc CommitBook ( CommitBookRequest ) returns ( Book )",
+		`post: "/{path=publishers/*/books/*}:commit"`,
+		"rpc RollbackBook ( RollbackBookRequest ) returns ( aep.api.Operation )",
+		"rpc TagBookRevision ( TagBookRevisionRequest ) returns ( Book )",
+		`post: "/{path=publishers/*/books/*}@{revision_id}:tagRevision"`,
+		"string revision_id = 10028",
+		"string tag = 10030",
+	} {
+		assert.Contains(t, protoContent, expected)
+	}
+}
+
+func TestEtag(t *testing.T) {
+	a := api.ExampleAPI()
+	a.Resources["book"].SupportsEtag = true
+	assert.NoError(t, api.AddImplicitFieldsAndValidate(a))
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	// the field is both on the resource and on the delete request.
+	assert.Equal(t, 2, strings.Count(protoContent, "string etag = 10031"))
+}
+
+func TestValidateOnly(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Create.SupportsValidateOnly = true
+	book.Methods.Delete.SupportsValidateOnly = true
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	assert.Equal(t, 2, strings.Count(protoContent, "bool validate_only = 10032"))
+}
+
+func TestRequestId(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Create.SupportsRequestId = true
+	book.Methods.Update.SupportsRequestId = true
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	assert.Equal(t, 2, strings.Count(protoContent, "string request_id = 10033"))
+}
+
+func TestReadMask(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.Get.SupportsReadMask = true
+	book.Methods.List.SupportsReadMask = true
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	assert.Equal(t, 2, strings.Count(protoContent, "google.protobuf.FieldMask read_mask = 10034"))
+}
+
+func TestOrderBy(t *testing.T) {
+	a := api.ExampleAPI()
+	book := a.Resources["book"]
+	book.Methods.List.SupportsOrderBy = true
+	book.Methods.List.OrderableFields = []string{"price", "published"}
+
+	protoString, err := APIToProtoString(a, "example/v1")
+	assert.NoError(t, err)
+	protoContent := string(protoString)
+	assert.Contains(t, protoContent, "string order_by = 10035")
+	assert.Contains(t, protoContent, "The results can be ordered by: price, published.")
+}
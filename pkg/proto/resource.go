@@ -77,6 +77,18 @@ func AddResource(r *api.Resource, a *api.API, fb *builder.FileBuilder, sb *build
 			return err
 		}
 	}
+	if r.Methods.Undelete != nil {
+		err := AddUndelete(a, r, resMsg, fb, sb)
+		if err != nil {
+			return err
+		}
+	}
+	if r.SupportsRevisions() {
+		err := AddRevisionMethods(a, r, resMsg, fb, sb)
+		if err != nil {
+			return err
+		}
+	}
 	if r.Methods.List != nil {
 		err := AddList(r, resMsg, fb, sb)
 		if err != nil {
@@ -98,12 +110,43 @@ func AddResource(r *api.Resource, a *api.API, fb *builder.FileBuilder, sb *build
 		}
 	}
 
+	if r.Methods.BatchGet != nil {
+		err := AddBatchGet(a, r, resMsg, fb, sb)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Methods.BatchCreate != nil {
+		err := AddBatchCreate(a, r, resMsg, fb, sb)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Methods.BatchUpdate != nil {
+		err := AddBatchUpdate(a, r, resMsg, fb, sb)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Methods.BatchDelete != nil {
+		err := AddBatchDelete(a, r, resMsg, fb, sb)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, cm := range r.CustomMethods {
 		err := AddCustomMethod(a, r, cm, resMsg, fb, ms, sb)
 		if err != nil {
 			return err
 		}
 	}
+	for _, cm := range r.CollectionCustomMethods {
+		err := AddCollectionCustomMethod(a, r, cm, resMsg, fb, ms, sb)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -272,6 +315,12 @@ func AddCreate(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuil
 		addIdField(r, mb)
 	}
 	addResourceField(r, resMsg, mb)
+	if r.Methods.Create.SupportsValidateOnly {
+		addValidateOnlyField(mb)
+	}
+	if r.Methods.Create.SupportsRequestId {
+		addRequestIdField(mb)
+	}
 	fb.AddMessage(mb)
 	method := buildMethod(
 		"Create"+toMessageName(r.Singular),
@@ -283,12 +332,14 @@ func AddCreate(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuil
 		LeadingComment: fmt.Sprintf("An aep-compliant Create method for %v.", r.Singular),
 	})
 	bodyField := cases.KebabToSnakeCase(r.Singular)
-	proto.SetExtension(method.Options, annotations.E_Http, &annotations.HttpRule{
-		Pattern: &annotations.HttpRule_Post{
-			Post: generateParentHTTPPath(r),
-		},
-		Body: bodyField,
-	})
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateParentHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Post{
+				Post: path,
+			},
+			Body: bodyField,
+		}
+	}))
 	method_signature := []string{bodyField}
 	if len(r.Parents) > 0 {
 		method_signature = []string{constants.FIELD_PARENT_NAME, bodyField}
@@ -306,6 +357,9 @@ func AddGet(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder
 		LeadingComment: fmt.Sprintf("Request message for the Get%v method", r.Singular),
 	})
 	addPathField(a, r, mb)
+	if r.Methods.Get.SupportsReadMask {
+		addReadMaskField(mb)
+	}
 	fb.AddMessage(mb)
 	method := builder.NewMethod("Get"+toMessageName(r.Singular),
 		builder.RpcTypeMessage(mb, false),
@@ -315,11 +369,13 @@ func AddGet(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder
 		LeadingComment: fmt.Sprintf("An aep-compliant Get method for %v.", r.Singular),
 	})
 	options := &descriptorpb.MethodOptions{}
-	proto.SetExtension(options, annotations.E_Http, &annotations.HttpRule{
-		Pattern: &annotations.HttpRule_Get{
-			Get: fmt.Sprintf("/{path=%v}", generateHTTPPath(r)),
-		},
-	})
+	proto.SetExtension(options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Get{
+				Get: fmt.Sprintf("/{path=%v}", path),
+			},
+		}
+	}))
 	proto.SetExtension(options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{constants.FIELD_PATH_NAME}, ","),
 	})
@@ -347,6 +403,12 @@ func AddUpdate(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuil
 		})
 	updateMaskField.SetJsonName(constants.FIELD_UPDATE_MASK_NAME)
 	mb.AddField(updateMaskField)
+	if r.Methods.Update.SupportsValidateOnly {
+		addValidateOnlyField(mb)
+	}
+	if r.Methods.Update.SupportsRequestId {
+		addRequestIdField(mb)
+	}
 	fb.AddMessage(mb)
 	method := buildMethod(
 		"Update"+toMessageName(r.Singular),
@@ -358,12 +420,14 @@ func AddUpdate(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuil
 		LeadingComment: fmt.Sprintf("An aep-compliant Update method for %v.", r.Singular),
 	})
 	body_field := cases.KebabToSnakeCase(r.Singular)
-	proto.SetExtension(method.Options, annotations.E_Http, &annotations.HttpRule{
-		Pattern: &annotations.HttpRule_Patch{
-			Patch: fmt.Sprintf("/{path=%v}", generateHTTPPath(r)),
-		},
-		Body: body_field,
-	})
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Patch{
+				Patch: fmt.Sprintf("/{path=%v}", path),
+			},
+			Body: body_field,
+		}
+	}))
 	proto.SetExtension(method.Options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{body_field, constants.FIELD_UPDATE_MASK_NAME}, ","),
 	})
@@ -382,25 +446,74 @@ func AddDelete(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuil
 	if len(r.Children) > 0 {
 		addForceField(a, r, mb)
 	}
+	if r.SupportsEtag {
+		addEtagField(mb)
+	}
+	if r.Methods.Delete.SupportsValidateOnly {
+		addValidateOnlyField(mb)
+	}
+	if r.Methods.Delete.SupportsRequestId {
+		addRequestIdField(mb)
+	}
 	fb.AddMessage(mb)
 	emptyMd, err := desc.LoadMessageDescriptor("google.protobuf.Empty")
 	if err != nil {
 		return err
 	}
+	response := builder.RpcTypeImportedMessage(emptyMd, false)
+	if r.SupportsSoftDelete() {
+		// soft-deleted resources are returned to the caller.
+		response = resMsg.RpcType()
+	}
 	method := buildMethod(
 		"Delete"+toMessageName(r.Singular),
 		builder.RpcTypeMessage(mb, false),
-		builder.RpcTypeImportedMessage(emptyMd, false),
+		response,
 		r.Methods.Delete.IsLongRunning,
 	)
 	method.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("An aep-compliant Delete method for %v.", r.Singular),
 	})
-	proto.SetExtension(method.Options, annotations.E_Http, &annotations.HttpRule{
-		Pattern: &annotations.HttpRule_Delete{
-			Delete: fmt.Sprintf("/{path=%v}", generateHTTPPath(r)),
-		},
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Delete{
+				Delete: fmt.Sprintf("/{path=%v}", path),
+			},
+		}
+	}))
+	proto.SetExtension(method.Options, annotations.E_MethodSignature, []string{
+		strings.Join([]string{constants.FIELD_PATH_NAME}, ","),
 	})
+	sb.AddMethod(method)
+	return nil
+}
+
+// AddUndelete adds an Undelete method for the resource, along with
+// any required messages.
+func AddUndelete(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
+	mb := builder.NewMessage("Undelete" + toMessageName(r.Singular) + "Request")
+	mb.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("Request message for the Undelete%v method", toMessageName(r.Singular)),
+	})
+	addPathField(a, r, mb)
+	fb.AddMessage(mb)
+	method := buildMethod(
+		"Undelete"+toMessageName(r.Singular),
+		builder.RpcTypeMessage(mb, false),
+		resMsg.RpcType(),
+		r.Methods.Undelete.IsLongRunning,
+	)
+	method.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("An aep-compliant Undelete method for %v.", r.Singular),
+	})
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Post{
+				Post: fmt.Sprintf("/{path=%v}:undelete", path),
+			},
+			Body: "*",
+		}
+	}))
 	proto.SetExtension(method.Options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{constants.FIELD_PATH_NAME}, ","),
 	})
@@ -408,6 +521,147 @@ func AddDelete(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuil
 	return nil
 }
 
+// AddRevisionMethods adds the revision methods of the resource
+// (aep.dev/162), along with any required messages.
+func AddRevisionMethods(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
+	name := toMessageName(r.Singular)
+	if r.Methods.ListRevisions != nil {
+		reqMb := builder.NewMessage("List" + name + "RevisionsRequest")
+		reqMb.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("Request message for the List%vRevisions method", name),
+		})
+		addPathField(a, r, reqMb)
+		addPageToken(r, reqMb)
+		maxPageSizeField := builder.NewField(constants.FIELD_MAX_PAGE_SIZE_NAME, builder.FieldTypeInt32()).
+			SetNumber(constants.FIELD_MAX_PAGE_SIZE_NUMBER).
+			SetComments(builder.Comments{
+				LeadingComment: "The maximum number of revisions to return in a single page.",
+			})
+		maxPageSizeField.SetJsonName(constants.FIELD_MAX_PAGE_SIZE_NAME)
+		reqMb.AddField(maxPageSizeField)
+		fb.AddMessage(reqMb)
+		respMb := builder.NewMessage("List" + name + "RevisionsResponse")
+		respMb.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("Response message for the List%vRevisions method", name),
+		})
+		addResourcesField(r, resMsg, respMb)
+		addNextPageToken(r, respMb)
+		fb.AddMessage(respMb)
+		method := builder.NewMethod("List"+name+"Revisions",
+			builder.RpcTypeMessage(reqMb, false),
+			builder.RpcTypeMessage(respMb, false),
+		)
+		method.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("An aep-compliant ListRevisions method for %v.", r.Singular),
+		})
+		options := &descriptorpb.MethodOptions{}
+		proto.SetExtension(options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+			return &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Get{
+					Get: fmt.Sprintf("/{path=%v}/revisions", path),
+				},
+			}
+		}))
+		proto.SetExtension(options, annotations.E_MethodSignature, []string{
+			strings.Join([]string{constants.FIELD_PATH_NAME}, ","),
+		})
+		method.SetOptions(options)
+		sb.AddMethod(method)
+	}
+	if r.Methods.GetRevision != nil {
+		mb := builder.NewMessage("Get" + name + "RevisionRequest")
+		mb.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("Request message for the Get%vRevision method", name),
+		})
+		addPathField(a, r, mb)
+		addRevisionIdField(mb)
+		fb.AddMessage(mb)
+		method := builder.NewMethod("Get"+name+"Revision",
+			builder.RpcTypeMessage(mb, false),
+			resMsg.RpcType(),
+		)
+		method.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("An aep-compliant GetRevision method for %v.", r.Singular),
+		})
+		options := &descriptorpb.MethodOptions{}
+		proto.SetExtension(options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+			return &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Get{
+					Get: fmt.Sprintf("/{path=%v}@{%v}", path, constants.FIELD_REVISION_ID_NAME),
+				},
+			}
+		}))
+		proto.SetExtension(options, annotations.E_MethodSignature, []string{
+			strings.Join([]string{constants.FIELD_PATH_NAME, constants.FIELD_REVISION_ID_NAME}, ","),
+		})
+		method.SetOptions(options)
+		sb.AddMethod(method)
+	}
+	if r.Methods.Commit != nil {
+		mb := builder.NewMessage("Commit" + name + "Request")
+		mb.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("Request message for the Commit%v method", name),
+		})
+		addPathField(a, r, mb)
+		fb.AddMessage(mb)
+		addRevisionMethod(r, "Commit", "Commit"+name, "/{path=%v}:commit", mb, resMsg, r.Methods.Commit.IsLongRunning, sb)
+	}
+	if r.Methods.Rollback != nil {
+		mb := builder.NewMessage("Rollback" + name + "Request")
+		mb.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("Request message for the Rollback%v method", name),
+		})
+		addPathField(a, r, mb)
+		addRevisionIdField(mb)
+		fb.AddMessage(mb)
+		addRevisionMethod(r, "Rollback", "Rollback"+name, "/{path=%v}:rollback", mb, resMsg, r.Methods.Rollback.IsLongRunning, sb)
+	}
+	if r.Methods.TagRevision != nil {
+		mb := builder.NewMessage("Tag" + name + "RevisionRequest")
+		mb.SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("Request message for the Tag%vRevision method", name),
+		})
+		addPathField(a, r, mb)
+		addRevisionIdField(mb)
+		f := builder.NewField(constants.FIELD_TAG_NAME, builder.FieldTypeString()).
+			SetNumber(constants.FIELD_TAG_NUMBER).
+			SetComments(builder.Comments{
+				LeadingComment: "The tag to add to the revision.",
+			})
+		f.SetJsonName(constants.FIELD_TAG_NAME)
+		mb.AddField(f)
+		fb.AddMessage(mb)
+		addRevisionMethod(r, "TagRevision", "Tag"+name+"Revision", "/{path=%v}@{"+constants.FIELD_REVISION_ID_NAME+"}:tagRevision", mb, resMsg, false, sb)
+	}
+	return nil
+}
+
+// addRevisionMethod adds a POST method of the given kind (e.g. Commit),
+// which responds with the resource. pathTemplate is formatted with each of the HTTP paths of the resource.
+func addRevisionMethod(r *api.Resource, kind string, methodName string, pathTemplate string, mb *builder.MessageBuilder, resMsg Message, isLongRunning bool, sb *builder.ServiceBuilder) {
+	method := buildMethod(
+		methodName,
+		builder.RpcTypeMessage(mb, false),
+		resMsg.RpcType(),
+		isLongRunning,
+	)
+	method.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("An aep-compliant %v method for %v.", kind, r.Singular),
+	})
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Post{
+				Post: fmt.Sprintf(pathTemplate, path),
+			},
+			Body: "*",
+		}
+	}))
+	proto.SetExtension(method.Options, annotations.E_MethodSignature, []string{
+		strings.Join([]string{constants.FIELD_PATH_NAME}, ","),
+	})
+	sb.AddMethod(method)
+}
+
 func AddList(r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
 	// add the resource message
 	// create request messages
@@ -442,6 +696,31 @@ func AddList(r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *build
 		filterField.SetJsonName(constants.FIELD_FILTER_NAME)
 		reqMb.AddField(filterField)
 	}
+	if r.Methods.List.SupportsReadMask {
+		addReadMaskField(reqMb)
+	}
+	if r.Methods.List.SupportsOrderBy {
+		comment := "The order of the results, e.g. \"create_time desc, title\"."
+		if len(r.Methods.List.OrderableFields) > 0 {
+			comment += fmt.Sprintf(" The results can be ordered by: %v.", strings.Join(r.Methods.List.OrderableFields, ", "))
+		}
+		orderByField := builder.NewField(constants.FIELD_ORDER_BY_NAME, builder.FieldTypeString()).
+			SetNumber(constants.FIELD_ORDER_BY_NUMBER).
+			SetComments(builder.Comments{
+				LeadingComment: comment,
+			})
+		orderByField.SetJsonName(constants.FIELD_ORDER_BY_NAME)
+		reqMb.AddField(orderByField)
+	}
+	if r.SupportsSoftDelete() {
+		showDeletedField := builder.NewField(constants.FIELD_SHOW_DELETED_NAME, builder.FieldTypeBool()).
+			SetNumber(constants.FIELD_SHOW_DELETED_NUMBER).
+			SetComments(builder.Comments{
+				LeadingComment: "If true, soft-deleted resources are included in the results.",
+			})
+		showDeletedField.SetJsonName(constants.FIELD_SHOW_DELETED_NAME)
+		reqMb.AddField(showDeletedField)
+	}
 	fb.AddMessage(reqMb)
 	respMb := builder.NewMessage("List" + toMessageName(r.Plural) + "Response")
 	respMb.SetComments(builder.Comments{
@@ -465,11 +744,13 @@ func AddList(r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *build
 		LeadingComment: fmt.Sprintf("An aep-compliant List method for %v.", r.Plural),
 	})
 	options := &descriptorpb.MethodOptions{}
-	proto.SetExtension(options, annotations.E_Http, &annotations.HttpRule{
-		Pattern: &annotations.HttpRule_Get{
-			Get: generateParentHTTPPath(r),
-		},
-	})
+	proto.SetExtension(options, annotations.E_Http, httpRule(generateParentHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Get{
+				Get: path,
+			},
+		}
+	}))
 	proto.SetExtension(options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{constants.FIELD_PARENT_NAME}, ","),
 	})
@@ -519,6 +800,12 @@ func AddApply(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuild
 	})
 	addPathField(a, r, mb)
 	addResourceField(r, resMsg, mb)
+	if r.Methods.Apply.SupportsValidateOnly {
+		addValidateOnlyField(mb)
+	}
+	if r.Methods.Apply.SupportsRequestId {
+		addRequestIdField(mb)
+	}
 	fb.AddMessage(mb)
 	method := buildMethod(
 		"Apply"+toMessageName(r.Singular),
@@ -529,19 +816,193 @@ func AddApply(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuild
 	method.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("An aep-compliant Apply method for %v.", r.Plural),
 	})
-	proto.SetExtension(method.Options, annotations.E_Http, &annotations.HttpRule{
-		Pattern: &annotations.HttpRule_Put{
-			Put: fmt.Sprintf("/{path=%v}", generateHTTPPath(r)),
-		},
-		// TODO: do a conversion to underscores instead.
-		Body: strings.ToLower(r.Singular),
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Put{
+				Put: fmt.Sprintf("/{path=%v}", path),
+			},
+			// TODO: do a conversion to underscores instead.
+			Body: strings.ToLower(r.Singular),
+		}
+	}))
+	sb.AddMethod(method)
+	return nil
+}
+
+// AddBatchGet adds a BatchGet method for the resource, along with
+// any required messages.
+func AddBatchGet(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
+	reqMb := builder.NewMessage("BatchGet" + toMessageName(r.Plural) + "Request")
+	reqMb.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("Request message for the BatchGet%v method", toMessageName(r.Plural)),
+	})
+	addParentField(r, reqMb)
+	addPathsField(a, r, reqMb)
+	fb.AddMessage(reqMb)
+	respMb := batchResponseMessage("BatchGet", r, resMsg)
+	fb.AddMessage(respMb)
+	method := buildMethod(
+		"BatchGet"+toMessageName(r.Plural),
+		builder.RpcTypeMessage(reqMb, false),
+		builder.RpcTypeMessage(respMb, false),
+		false,
+	)
+	method.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("An aep-compliant BatchGet method for %v.", r.Plural),
 	})
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateParentHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Get{
+				Get: path + ":batchGet",
+			},
+		}
+	}))
 	sb.AddMethod(method)
 	return nil
 }
 
+// AddBatchCreate adds a BatchCreate method for the resource, along
+// with any required messages. The Create request message must have
+// been added already.
+func AddBatchCreate(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
+	createMb := fb.GetMessage("Create" + toMessageName(r.Singular) + "Request")
+	if createMb == nil {
+		return fmt.Errorf("create request message not found for the batch create method of %s", r.Singular)
+	}
+	sb.AddMethod(addBatchRequestsMethod("BatchCreate", r, resMsg, createMb, r.Methods.BatchCreate.IsLongRunning, fb))
+	return nil
+}
+
+// AddBatchUpdate adds a BatchUpdate method for the resource, along
+// with any required messages. The Update request message must have
+// been added already.
+func AddBatchUpdate(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
+	updateMb := fb.GetMessage("Update" + toMessageName(r.Singular) + "Request")
+	if updateMb == nil {
+		return fmt.Errorf("update request message not found for the batch update method of %s", r.Singular)
+	}
+	sb.AddMethod(addBatchRequestsMethod("BatchUpdate", r, resMsg, updateMb, r.Methods.BatchUpdate.IsLongRunning, fb))
+	return nil
+}
+
+// AddBatchDelete adds a BatchDelete method for the resource, along
+// with any required messages.
+func AddBatchDelete(a *api.API, r *api.Resource, resMsg Message, fb *builder.FileBuilder, sb *builder.ServiceBuilder) error {
+	reqMb := builder.NewMessage("BatchDelete" + toMessageName(r.Plural) + "Request")
+	reqMb.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("Request message for the BatchDelete%v method", toMessageName(r.Plural)),
+	})
+	addParentField(r, reqMb)
+	addPathsField(a, r, reqMb)
+	fb.AddMessage(reqMb)
+	emptyMd, err := desc.LoadMessageDescriptor("google.protobuf.Empty")
+	if err != nil {
+		return err
+	}
+	method := buildMethod(
+		"BatchDelete"+toMessageName(r.Plural),
+		builder.RpcTypeMessage(reqMb, false),
+		builder.RpcTypeImportedMessage(emptyMd, false),
+		r.Methods.BatchDelete.IsLongRunning,
+	)
+	method.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("An aep-compliant BatchDelete method for %v.", r.Plural),
+	})
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateParentHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Post{
+				Post: path + ":batchDelete",
+			},
+			Body: "*",
+		}
+	}))
+	sb.AddMethod(method)
+	return nil
+}
+
+// addBatchRequestsMethod adds the messages of a batch method whose
+// request repeats the request message of a standard method, and
+// returns the method.
+func addBatchRequestsMethod(name string, r *api.Resource, resMsg Message, requestMb *builder.MessageBuilder, isLongRunning bool, fb *builder.FileBuilder) *builder.MethodBuilder {
+	reqMb := builder.NewMessage(name + toMessageName(r.Plural) + "Request")
+	reqMb.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("Request message for the %v%v method", name, toMessageName(r.Plural)),
+	})
+	addParentField(r, reqMb)
+	f := builder.NewField(constants.FIELD_REQUESTS_NAME, builder.FieldTypeMessage(requestMb)).
+		SetNumber(constants.FIELD_REQUESTS_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "The requests to perform in the batch.",
+		}).
+		SetRepeated()
+	f.SetJsonName(constants.FIELD_REQUESTS_NAME)
+	reqMb.AddField(f)
+	fb.AddMessage(reqMb)
+	respMb := batchResponseMessage(name, r, resMsg)
+	fb.AddMessage(respMb)
+	method := buildMethod(
+		name+toMessageName(r.Plural),
+		builder.RpcTypeMessage(reqMb, false),
+		builder.RpcTypeMessage(respMb, false),
+		isLongRunning,
+	)
+	method.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("An aep-compliant %v method for %v.", name, r.Plural),
+	})
+	customMethod := ":" + strings.ToLower(name[:1]) + name[1:]
+	proto.SetExtension(method.Options, annotations.E_Http, httpRule(generateParentHTTPPaths(r), func(path string) *annotations.HttpRule {
+		return &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Post{
+				Post: path + customMethod,
+			},
+			Body: "*",
+		}
+	}))
+	return method
+}
+
+// batchResponseMessage returns the response message of a batch method,
+// which holds the resulting resources.
+func batchResponseMessage(name string, r *api.Resource, resMsg Message) *builder.MessageBuilder {
+	respMb := builder.NewMessage(name + toMessageName(r.Plural) + "Response")
+	respMb.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("Response message for the %v%v method", name, toMessageName(r.Plural)),
+	})
+	addResourcesField(r, resMsg, respMb)
+	return respMb
+}
+
 func AddCustomMethod(a *api.API, r *api.Resource, cm *api.CustomMethod, resMsg Message, fb *builder.FileBuilder, m *MessageStorage, sb *builder.ServiceBuilder) error {
-	methodName := cases.KebabToCamelCase(cm.Name) + toMessageName(r.Singular)
+	http_paths := []string{}
+	for _, path := range generateHTTPPaths(r) {
+		http_paths = append(http_paths, fmt.Sprintf("/{path=%v}:%v", path, cm.Name))
+	}
+	return addCustomMethod(a, cm,
+		cases.KebabToCamelCase(cm.Name)+toMessageName(r.Singular),
+		fmt.Sprintf("%v a %v.", cm.Name, r.Singular),
+		func(mb *builder.MessageBuilder) { addPathField(a, r, mb) },
+		http_paths, fb, m, sb)
+}
+
+// AddCollectionCustomMethod adds a custom method that operates on the
+// collection of the resource (e.g. :search), along with any required
+// messages.
+func AddCollectionCustomMethod(a *api.API, r *api.Resource, cm *api.CustomMethod, resMsg Message, fb *builder.FileBuilder, m *MessageStorage, sb *builder.ServiceBuilder) error {
+	http_paths := []string{}
+	for _, path := range generateParentHTTPPaths(r) {
+		http_paths = append(http_paths, fmt.Sprintf("%v:%v", path, cm.Name))
+	}
+	return addCustomMethod(a, cm,
+		cases.KebabToCamelCase(cm.Name)+toMessageName(r.Plural),
+		fmt.Sprintf("%v %v.", cm.Name, r.Plural),
+		func(mb *builder.MessageBuilder) { addParentField(r, mb) },
+		http_paths, fb, m, sb)
+}
+
+// addCustomMethod adds a custom method with the given name. addTarget
+// adds the field identifying the resource or collection the method
+// operates on to the request message.
+func addCustomMethod(a *api.API, cm *api.CustomMethod, methodName string, comment string, addTarget func(*builder.MessageBuilder), http_paths []string, fb *builder.FileBuilder, m *MessageStorage, sb *builder.ServiceBuilder) error {
 	request := cm.Request
 	if request == nil {
 		request = &openapi.Schema{}
@@ -553,7 +1014,7 @@ func AddCustomMethod(a *api.API, r *api.Resource, cm *api.CustomMethod, resMsg M
 	requestMb.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("Request message for the %v method", cm.Name),
 	})
-	addPathField(a, r, requestMb)
+	addTarget(requestMb)
 	var responseMsg *builder.RpcType
 	if cm.Response != nil {
 		responseMb, err := GenerateMessage(methodName+"Response", cm.Response, a, m)
@@ -580,53 +1041,76 @@ func AddCustomMethod(a *api.API, r *api.Resource, cm *api.CustomMethod, resMsg M
 		cm.IsLongRunning,
 	)
 	method.SetComments(builder.Comments{
-		LeadingComment: fmt.Sprintf("%v a %v.", cm.Name, r.Singular),
+		LeadingComment: comment,
 	})
-	http_path := fmt.Sprintf("/{path=%v}:%v", generateHTTPPath(r), cm.Name)
 	switch cm.Method {
 	case "POST":
-		proto.SetExtension(method.Options, annotations.E_Http, &annotations.HttpRule{
-			Pattern: &annotations.HttpRule_Post{
-				Post: http_path,
-			},
-			Body: "*",
-		})
+		proto.SetExtension(method.Options, annotations.E_Http, httpRule(http_paths, func(path string) *annotations.HttpRule {
+			return &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Post{
+					Post: path,
+				},
+				Body: "*",
+			}
+		}))
 	case "GET":
-		proto.SetExtension(method.Options, annotations.E_Http, &annotations.HttpRule{
-			Pattern: &annotations.HttpRule_Get{
-				Get: http_path,
-			},
-		})
+		proto.SetExtension(method.Options, annotations.E_Http, httpRule(http_paths, func(path string) *annotations.HttpRule {
+			return &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Get{
+					Get: path,
+				},
+			}
+		}))
 	}
 	sb.AddMethod(method)
 	return nil
 }
 
-func generateHTTPPath(r *api.Resource) string {
-	elements := []string{api.CollectionName(r)}
-	if len(r.Parents) > 0 {
-		// TODO: handle multiple parents
-		p := r.ParentResources()[0]
-		for p != nil {
-			elements = append([]string{api.CollectionName(p)}, elements...)
-			if len(p.ParentResources()) == 0 {
-				break
-			}
-			p = p.ParentResources()[0]
+// generateHTTPPaths returns the HTTP path template of the resource
+// (e.g. publishers/*/books/*) for each of its patterns, starting with
+// the primary pattern.
+func generateHTTPPaths(r *api.Resource) []string {
+	paths := []string{}
+	for _, elems := range r.AllPatternElems() {
+		paths = append(paths, httpPathTemplate(elems))
+	}
+	return paths
+}
+
+// generateParentHTTPPaths returns the HTTP path of the collection of
+// the resource for each of its patterns, starting with the primary
+// pattern.
+func generateParentHTTPPaths(r *api.Resource) []string {
+	paths := []string{}
+	for _, elems := range r.AllPatternElems() {
+		collection := elems[len(elems)-2]
+		if len(elems) == 2 {
+			paths = append(paths, fmt.Sprintf("/%v", collection))
+			continue
 		}
+		parentPath := httpPathTemplate(elems[:len(elems)-2])
+		paths = append(paths, fmt.Sprintf("/{parent=%v}/%v", parentPath, collection))
 	}
-	return fmt.Sprintf("%v/*", strings.Join(elements, "/*/"))
+	return paths
 }
 
-func generateParentHTTPPath(r *api.Resource) string {
-	parentPath := ""
-	if len(r.Parents) == 0 {
-		return fmt.Sprintf("/%v", strings.ToLower(r.Plural))
+// httpPathTemplate replaces the variables of a pattern with wildcards.
+func httpPathTemplate(patternElems []string) string {
+	elems := slices.Clone(patternElems)
+	for i := 1; i < len(elems); i += 2 {
+		elems[i] = "*"
 	}
-	if len(r.Parents) > 0 {
-		parentPath = fmt.Sprintf("%v", generateHTTPPath(r.ParentResources()[0]))
+	return strings.Join(elems, "/")
+}
+
+// httpRule builds an HttpRule for the given paths. The first path is
+// the primary binding, and the others are added as additional bindings.
+func httpRule(paths []string, rule func(path string) *annotations.HttpRule) *annotations.HttpRule {
+	primary := rule(paths[0])
+	for _, path := range paths[1:] {
+		primary.AdditionalBindings = append(primary.AdditionalBindings, rule(path))
 	}
-	return fmt.Sprintf("/{parent=%v}/%v", parentPath, api.CollectionName(r))
+	return primary
 }
 
 func addParentField(r *api.Resource, mb *builder.MessageBuilder) {
@@ -674,6 +1158,25 @@ func addPathField(a *api.API, r *api.Resource, mb *builder.MessageBuilder) {
 	mb.AddField(f)
 }
 
+func addPathsField(a *api.API, r *api.Resource, mb *builder.MessageBuilder) {
+	o := &descriptorpb.FieldOptions{}
+	// Keep Google API annotation for backward compatibility
+	proto.SetExtension(o, annotations.E_FieldBehavior, []annotations.FieldBehavior{annotations.FieldBehavior_REQUIRED})
+	proto.SetExtension(o, apipb.E_FieldInfo, &apipb.FieldInfo{
+		ResourceReference: []string{fmt.Sprintf("%s/%s", a.Name, r.Singular)},
+		FieldBehavior:     []apipb.FieldBehavior{apipb.FieldBehavior_FIELD_BEHAVIOR_REQUIRED},
+	})
+	f := builder.NewField(constants.FIELD_PATHS_NAME, builder.FieldTypeString()).
+		SetNumber(constants.FIELD_PATHS_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: fmt.Sprintf("The paths of the %v to operate on.", r.Plural),
+		}).
+		SetRepeated().
+		SetOptions(o)
+	f.SetJsonName(constants.FIELD_PATHS_NAME)
+	mb.AddField(f)
+}
+
 func addResourceField(r *api.Resource, resMsg Message, mb *builder.MessageBuilder) {
 	o := &descriptorpb.FieldOptions{}
 	// Keep Google API annotation for backward compatibility
@@ -715,6 +1218,63 @@ func addNextPageToken(_ *api.Resource, mb *builder.MessageBuilder) {
 	mb.AddField(f)
 }
 
+func addValidateOnlyField(mb *builder.MessageBuilder) {
+	f := builder.NewField(constants.FIELD_VALIDATE_ONLY_NAME, builder.FieldTypeBool()).
+		SetNumber(constants.FIELD_VALIDATE_ONLY_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "If true, the request is validated, but not applied.",
+		})
+	f.SetJsonName(constants.FIELD_VALIDATE_ONLY_NAME)
+	mb.AddField(f)
+}
+
+func addReadMaskField(mb *builder.MessageBuilder) {
+	fieldMaskDescriptor, _ := desc.LoadMessageDescriptorForType(reflect.TypeOf(fieldmaskpb.FieldMask{}))
+	f := builder.NewField(constants.FIELD_READ_MASK_NAME, builder.FieldTypeImportedMessage(fieldMaskDescriptor)).
+		SetNumber(constants.FIELD_READ_MASK_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "The fields of the resource to return. All fields are returned if unset.",
+		})
+	f.SetJsonName(constants.FIELD_READ_MASK_NAME)
+	mb.AddField(f)
+}
+
+func addRequestIdField(mb *builder.MessageBuilder) {
+	f := builder.NewField(constants.FIELD_REQUEST_ID_NAME, builder.FieldTypeString()).
+		SetNumber(constants.FIELD_REQUEST_ID_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "A unique id for the request. A request that is retried with the same id is only applied once.",
+		})
+	f.SetJsonName(constants.FIELD_REQUEST_ID_NAME)
+	mb.AddField(f)
+}
+
+func addEtagField(mb *builder.MessageBuilder) {
+	o := &descriptorpb.FieldOptions{}
+	proto.SetExtension(o, annotations.E_FieldBehavior, []annotations.FieldBehavior{annotations.FieldBehavior_OPTIONAL})
+	proto.SetExtension(o, apipb.E_FieldInfo, &apipb.FieldInfo{
+		FieldBehavior: []apipb.FieldBehavior{apipb.FieldBehavior_FIELD_BEHAVIOR_OPTIONAL},
+	})
+	f := builder.NewField(constants.FIELD_ETAG_NAME, builder.FieldTypeString()).
+		SetNumber(constants.FIELD_ETAG_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "If set, the resource is only deleted if its etag matches.",
+		}).
+		SetOptions(o)
+	f.SetJsonName(constants.FIELD_ETAG_NAME)
+	mb.AddField(f)
+}
+
+func addRevisionIdField(mb *builder.MessageBuilder) {
+	f := builder.NewField(constants.FIELD_REVISION_ID_NAME, builder.FieldTypeString()).
+		SetNumber(constants.FIELD_REVISION_ID_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "The id of the revision.",
+		})
+	f.SetJsonName(constants.FIELD_REVISION_ID_NAME)
+	mb.AddField(f)
+}
+
 func addForceField(_ *api.API, _ *api.Resource, mb *builder.MessageBuilder) {
 	o := &descriptorpb.FieldOptions{}
 	// Keep Google API annotation for backward compatibility
@@ -733,12 +1293,9 @@ func addForceField(_ *api.API, _ *api.Resource, mb *builder.MessageBuilder) {
 }
 
 func resourceDescriptor(a *api.API, r *api.Resource) *apipb.ResourceDescriptor {
-	patterns := []string{
-		strings.Join(r.PatternElems(), "/"),
-	}
 	return &apipb.ResourceDescriptor{
 		Type:     fmt.Sprintf("%s/%s", a.Name, r.Singular),
-		Pattern:  patterns,
+		Pattern:  r.GetPatterns(),
 		Singular: r.Singular,
 		Plural:   r.Plural,
 	}
@@ -0,0 +1,316 @@
+// Package yamlutil converts YAML documents into JSON, so that they can
+// be decoded by the same code paths as JSON input, while keeping
+// track of where each value was defined in the original source.
+package yamlutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a location in a YAML source document.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// SourceMap maps dotted value paths (e.g. "resources.book.methods")
+// to the position in the YAML source where the value was defined.
+// Sequence elements are addressed by their index, e.g. "servers.0.url".
+//
+// The path format matches the one used by encoding/json in
+// UnmarshalTypeError.Field.
+type SourceMap map[string]Position
+
+// Lookup returns the position of the value at path. If the path
+// itself is not present, the position of the closest ancestor is
+// returned instead.
+func (sm SourceMap) Lookup(path string) (Position, bool) {
+	for {
+		if p, ok := sm[path]; ok {
+			return p, true
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	p, ok := sm[""]
+	return p, ok
+}
+
+// Error is an error that occurred at a specific location in a YAML
+// document.
+type Error struct {
+	Path string
+	Position
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%v: %v", e.Position, e.Err)
+	}
+	return fmt.Sprintf("%v (%s): %v", e.Position, e.Path, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsJSON reports whether data looks like a JSON document, i.e. the
+// first non-whitespace character opens an object or an array.
+func IsJSON(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// ToJSON converts YAML data into JSON.
+//
+// Anchors, aliases and merge keys ("<<") are resolved. If the input
+// contains multiple documents, they must all be mappings, and are
+// deep-merged in order: nested mappings are combined, while defining
+// the same non-mapping value in two documents is an error.
+//
+// The returned SourceMap records the position of every value in the
+// resulting JSON.
+func ToJSON(data []byte) ([]byte, SourceMap, error) {
+	sm := SourceMap{}
+	value, err := decodeDocuments(data, sm)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error marshalling YAML as JSON: %w", err)
+	}
+	return out, sm, nil
+}
+
+// Unmarshal decodes YAML data into v, using the JSON struct tags
+// of v. Errors that can be attributed to a particular value are
+// returned as an *Error carrying the position of that value.
+func Unmarshal(data []byte, v interface{}) error {
+	jsonData, sm, err := ToJSON(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(jsonData, v); err != nil {
+		return sm.WrapError(err)
+	}
+	return nil
+}
+
+// WrapError annotates an error returned by encoding/json with the
+// position of the offending value, when it can be determined.
+func (sm SourceMap) WrapError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if pos, ok := sm.Lookup(typeErr.Field); ok {
+			return &Error{
+				Path:     typeErr.Field,
+				Position: pos,
+				Err:      fmt.Errorf("cannot unmarshal %s into a value of type %v", typeErr.Value, typeErr.Type),
+			}
+		}
+	}
+	return err
+}
+
+func decodeDocuments(data []byte, sm SourceMap) (interface{}, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var result interface{}
+	documents := 0
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing YAML: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		docSM := SourceMap{}
+		value, err := nodeToValue(doc.Content[0], "", docSM)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		documents++
+		if documents == 1 {
+			result = value
+			for k, p := range docSM {
+				sm[k] = p
+			}
+			continue
+		}
+		result, err = mergeDocument(result, value, "", sm, docSM)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func nodeToValue(n *yaml.Node, path string, sm SourceMap) (interface{}, error) {
+	if _, ok := sm[path]; !ok {
+		sm[path] = Position{Line: n.Line, Column: n.Column}
+	}
+	switch n.Kind {
+	case yaml.AliasNode:
+		return nodeToValue(n.Alias, path, sm)
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return nodeToValue(n.Content[0], path, sm)
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(n.Content))
+		for i, c := range n.Content {
+			v, err := nodeToValue(c, joinPath(path, strconv.Itoa(i)), sm)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case yaml.MappingNode:
+		m := map[string]interface{}{}
+		if err := addMappingValues(n, path, m, sm, false); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case yaml.ScalarNode:
+		return scalarValue(n, path)
+	}
+	return nil, &Error{
+		Path:     path,
+		Position: Position{Line: n.Line, Column: n.Column},
+		Err:      fmt.Errorf("unsupported YAML node kind %v", n.Kind),
+	}
+}
+
+// addMappingValues adds the key / value pairs of the mapping node n
+// to m. Values brought in via merge keys never override explicitly
+// set keys, per the YAML merge key specification.
+func addMappingValues(n *yaml.Node, path string, m map[string]interface{}, sm SourceMap, merged bool) error {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml.MappingNode {
+		return &Error{
+			Path:     path,
+			Position: Position{Line: n.Line, Column: n.Column},
+			Err:      fmt.Errorf("merge key must reference a mapping"),
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Tag == "!!merge" {
+			sources := []*yaml.Node{v}
+			if v.Kind == yaml.SequenceNode {
+				sources = v.Content
+			}
+			for _, s := range sources {
+				if err := addMappingValues(s, path, m, sm, true); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		key := k.Value
+		childPath := joinPath(path, key)
+		if _, exists := m[key]; exists {
+			if merged {
+				continue
+			}
+			// an explicit key overrides one brought in by a merge key.
+			for p := range sm {
+				if p == childPath || strings.HasPrefix(p, childPath+".") {
+					delete(sm, p)
+				}
+			}
+		}
+		value, err := nodeToValue(v, childPath, sm)
+		if err != nil {
+			return err
+		}
+		m[key] = value
+	}
+	return nil
+}
+
+func scalarValue(n *yaml.Node, path string) (interface{}, error) {
+	switch n.ShortTag() {
+	case "!!str", "!!binary", "!!timestamp":
+		return n.Value, nil
+	case "!!null":
+		return nil, nil
+	}
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, &Error{
+			Path:     path,
+			Position: Position{Line: n.Line, Column: n.Column},
+			Err:      err,
+		}
+	}
+	return v, nil
+}
+
+func mergeDocument(into, from interface{}, path string, sm, fromSM SourceMap) (interface{}, error) {
+	intoMap, intoOk := into.(map[string]interface{})
+	fromMap, fromOk := from.(map[string]interface{})
+	if !intoOk || !fromOk {
+		first, _ := sm.Lookup(path)
+		second, _ := fromSM.Lookup(path)
+		return nil, &Error{
+			Path:     path,
+			Position: second,
+			Err:      fmt.Errorf("value is already defined at %v", first),
+		}
+	}
+	for k, v := range fromMap {
+		childPath := joinPath(path, k)
+		existing, ok := intoMap[k]
+		if !ok {
+			intoMap[k] = v
+			for p, pos := range fromSM {
+				if p == childPath || strings.HasPrefix(p, childPath+".") {
+					sm[p] = pos
+				}
+			}
+			continue
+		}
+		merged, err := mergeDocument(existing, v, childPath, sm, fromSM)
+		if err != nil {
+			return nil, err
+		}
+		intoMap[k] = merged
+	}
+	return intoMap, nil
+}
+
+func joinPath(path, elem string) string {
+	if path == "" {
+		return elem
+	}
+	return path + "." + elem
+}
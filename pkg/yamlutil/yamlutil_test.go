@@ -0,0 +1,95 @@
+package yamlutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedJSON string
+		expectedErr  string
+	}{
+		{
+			name:         "simple mapping",
+			input:        "name: foo\ncount: 2\nenabled: true\n",
+			expectedJSON: `{"count":2,"enabled":true,"name":"foo"}`,
+		},
+		{
+			name:         "non-string keys are stringified",
+			input:        "responses:\n  200:\n    description: ok\n",
+			expectedJSON: `{"responses":{"200":{"description":"ok"}}}`,
+		},
+		{
+			name:         "anchors and aliases",
+			input:        "a: &x\n  b: 1\nc: *x\n",
+			expectedJSON: `{"a":{"b":1},"c":{"b":1}}`,
+		},
+		{
+			name:         "merge keys do not override explicit keys",
+			input:        "base: &base\n  a: 1\n  b: 2\nderived:\n  b: 3\n  <<: *base\n",
+			expectedJSON: `{"base":{"a":1,"b":2},"derived":{"a":1,"b":3}}`,
+		},
+		{
+			name:         "multiple documents are merged",
+			input:        "resources:\n  a: {x: 1}\n---\nresources:\n  b: {x: 2}\n",
+			expectedJSON: `{"resources":{"a":{"x":1},"b":{"x":2}}}`,
+		},
+		{
+			name:         "empty documents are skipped",
+			input:        "---\n---\nname: foo\n",
+			expectedJSON: `{"name":"foo"}`,
+		},
+		{
+			name:        "conflicting values across documents",
+			input:       "name: foo\n---\nname: bar\n",
+			expectedErr: "line 3, column 7 (name): value is already defined at line 1, column 7",
+		},
+		{
+			name:        "syntax error",
+			input:       "a: [1, 2\n",
+			expectedErr: "error parsing YAML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := ToJSON([]byte(tt.input))
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedJSON, string(out))
+		})
+	}
+}
+
+func TestUnmarshalReportsPosition(t *testing.T) {
+	type method struct {
+		IsLongRunning bool `json:"is_long_running"`
+	}
+	type resource struct {
+		Methods map[string]method `json:"methods"`
+	}
+	input := "methods:\n  get: {}\n  create:\n    is_long_running: \"yes\"\n"
+	var r resource
+	err := Unmarshal([]byte(input), &r)
+	require.Error(t, err)
+	var yamlErr *Error
+	require.True(t, errors.As(err, &yamlErr), "expected a *yamlutil.Error, got %T", err)
+	assert.Equal(t, "methods.create.is_long_running", yamlErr.Path)
+	assert.Equal(t, Position{Line: 4, Column: 22}, yamlErr.Position)
+}
+
+func TestIsJSON(t *testing.T) {
+	assert.True(t, IsJSON([]byte("  \n{\"a\": 1}")))
+	assert.True(t, IsJSON([]byte("[1]")))
+	assert.False(t, IsJSON([]byte("a: 1")))
+	assert.False(t, IsJSON([]byte("")))
+}